@@ -0,0 +1,68 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/alibaba/git-repo-go/path"
+)
+
+// IsMergeInProgress checks whether is in middle of a merge.
+func (v Repository) IsMergeInProgress() bool {
+	return path.Exist(filepath.Join(v.RepoDir(), "MERGE_HEAD"))
+}
+
+// IsCherryPickInProgress checks whether is in middle of a cherry-pick.
+func (v Repository) IsCherryPickInProgress() bool {
+	return path.Exist(filepath.Join(v.RepoDir(), "CHERRY_PICK_HEAD"))
+}
+
+// IsBisectInProgress checks whether is in middle of a bisect.
+func (v Repository) IsBisectInProgress() bool {
+	return path.Exist(filepath.Join(v.RepoDir(), "BISECT_LOG"))
+}
+
+// AbortAll aborts any in-progress rebase, merge, cherry-pick, or bisect,
+// leaving the repository on its prior HEAD. It collects failures from
+// each abort attempted and returns them as a single combined error.
+func (v *Repository) AbortAll() error {
+	var errs []string
+
+	if v.IsRebaseInProgress() {
+		if err := v.runGitAbort("rebase", "--abort"); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if v.IsMergeInProgress() {
+		if err := v.runGitAbort("merge", "--abort"); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if v.IsCherryPickInProgress() {
+		if err := v.runGitAbort("cherry-pick", "--abort"); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if v.IsBisectInProgress() {
+		if err := v.runGitAbort("bisect", "reset"); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("fail to abort all in-progress operations: %s", strings.Join(errs, "; "))
+}
+
+func (v Repository) runGitAbort(args ...string) error {
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = v.WorktreePath()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s: %s", args[0], err, out)
+	}
+	return nil
+}
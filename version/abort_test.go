@@ -0,0 +1,51 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestAbortAllAbortsInProgressMerge(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "test")
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("base\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-m", "base")
+
+	runGit(t, dir, "checkout", "-b", "topic")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("topic\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "commit", "-am", "topic change")
+
+	runGit(t, dir, "checkout", "master")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("master\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "commit", "-am", "master change")
+
+	// This merge conflicts and leaves MERGE_HEAD behind.
+	cmd := exec.Command(GIT, "merge", "topic")
+	cmd.Dir = dir
+	cmd.Run()
+
+	gitDir := filepath.Join(dir, ".git")
+	repo := &Repository{GitDir: gitDir, DotGit: gitDir}
+	if !repo.IsMergeInProgress() {
+		t.Fatal("expected a merge conflict to be in progress")
+	}
+
+	if err := repo.AbortAll(); err != nil {
+		t.Fatalf("AbortAll: %s", err)
+	}
+	if repo.IsMergeInProgress() {
+		t.Errorf("expected merge to be aborted")
+	}
+}
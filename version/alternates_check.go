@@ -0,0 +1,77 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (v Repository) alternatesFile() string {
+	return filepath.Join(v.GitDir, "objects", "info", "alternates")
+}
+
+// CheckAlternates reads objects/info/alternates and verifies that every
+// entry resolves, relative to GitDir/objects, to a directory that actually
+// contains objects (a "pack" subdirectory or loose object directories). An
+// empty or missing alternates file is not an error, matching HasAlternates.
+func (v Repository) CheckAlternates() error {
+	altFile := v.alternatesFile()
+	f, err := os.Open(altFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("fail to open %s: %s", altFile, err)
+	}
+	defer f.Close()
+
+	objectsDir := filepath.Join(v.GitDir, "objects")
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		altDir := line
+		if !filepath.IsAbs(altDir) {
+			altDir = filepath.Join(objectsDir, altDir)
+		}
+		if !alternateHasObjects(altDir) {
+			return fmt.Errorf("alternate %s (from %s) does not exist or has no objects", altDir, altFile)
+		}
+	}
+	return s.Err()
+}
+
+func alternateHasObjects(dir string) bool {
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return false
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "pack")); err == nil && fi.IsDir() {
+		return true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && len(entry.Name()) == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// RepairAlternates rewrites objects/info/alternates from scratch, pointing
+// only at reference, discarding whatever broken entries were there before.
+// Use this once CheckAlternates has confirmed the on-disk state is bad,
+// e.g. after moving .repo between machines.
+func (v Repository) RepairAlternates(reference string) error {
+	if reference == "" {
+		return os.Remove(v.alternatesFile())
+	}
+	v.setAlternates(reference)
+	return nil
+}
@@ -0,0 +1,74 @@
+package version
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+
+	"github.com/alibaba/git-repo-go/file"
+	"github.com/alibaba/git-repo-go/path"
+)
+
+// attributesFile is the info/attributes file git consults in addition to
+// (and with priority over) the worktree .gitattributes, without ever
+// touching the worktree itself.
+func (v Repository) attributesFile() string {
+	return filepath.Join(v.CommonDir(), "info", "attributes")
+}
+
+// EnsureAttributes appends lines to CommonDir()/info/attributes, skipping
+// any that are already present so repeated calls are idempotent.
+func (v *Repository) EnsureAttributes(lines []string) error {
+	existing, err := v.readAttributes()
+	if err != nil {
+		return err
+	}
+
+	have := make(map[string]struct{}, len(existing))
+	for _, l := range existing {
+		have[l] = struct{}{}
+	}
+
+	f, err := file.New(v.attributesFile()).OpenCreateRewrite()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, l := range existing {
+		if _, err := f.WriteString(l + "\n"); err != nil {
+			return err
+		}
+	}
+	for _, l := range lines {
+		if _, ok := have[l]; ok {
+			continue
+		}
+		have[l] = struct{}{}
+		if _, err := f.WriteString(l + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v Repository) readAttributes() ([]string, error) {
+	f := v.attributesFile()
+	if !path.IsFile(f) {
+		return nil, nil
+	}
+	fh, err := os.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var lines []string
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		if s.Text() != "" {
+			lines = append(lines, s.Text())
+		}
+	}
+	return lines, s.Err()
+}
@@ -0,0 +1,82 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	log "github.com/jiangxin/multi-log"
+	"gopkg.in/src-d/go-git.v4"
+)
+
+// ErrUnsupported is returned by go-git backed operations that hit a git
+// feature go-git does not implement, signalling that the caller should
+// fall back to the git exec backend.
+var ErrUnsupported = errors.New("operation unsupported by go-git backend")
+
+// fetchWithArgs fetches remoteName into the repository, preferring the
+// go-git backend unless RepoSettings.PreferGitBinary is set or the go-git
+// implementation reports ErrUnsupported, in which case it falls back to
+// invoking git directly.
+func (v Repository) fetchWithArgs(remoteName string, args ...string) error {
+	preferExec := v.Settings != nil && v.Settings.PreferGitBinary
+	args = v.withDepthArgs(args)
+	args, err := v.withFilterArgs(remoteName, args)
+	if err != nil {
+		return err
+	}
+
+	if !preferExec {
+		err := v.fetchGoGit(remoteName, args...)
+		if err == nil {
+			log.Debugf("Fetch %s: handled by %s", RedactURL(remoteName), backendGoGit)
+			return nil
+		}
+		if !errors.Is(err, ErrUnsupported) {
+			return err
+		}
+	}
+
+	if err := v.fetchExec(remoteName, args...); err != nil {
+		return err
+	}
+	log.Debugf("Fetch %s: handled by %s", RedactURL(remoteName), backendExec)
+
+	if corrupt, err := v.verifyAfterFetch(); err != nil {
+		return err
+	} else if len(corrupt) > 0 {
+		return fmt.Errorf("fetch %s produced corrupt packs: %s", remoteName, corrupt)
+	}
+	return nil
+}
+
+func (v Repository) fetchGoGit(remoteName string, args ...string) error {
+	raw := v.Raw()
+	if raw == nil {
+		return ErrUnsupported
+	}
+	if len(args) > 0 {
+		// go-git does not support arbitrary fetch flags (e.g. --depth,
+		// --prune); defer to the exec backend for those.
+		return ErrUnsupported
+	}
+	remote, err := raw.Remote(remoteName)
+	if err != nil {
+		return ErrUnsupported
+	}
+	if err := remote.Fetch(&git.FetchOptions{}); err != nil && err.Error() != "already up-to-date" {
+		return fmt.Errorf("fail to fetch %s: %s", remoteName, err)
+	}
+	return nil
+}
+
+func (v Repository) fetchExec(remoteName string, args ...string) error {
+	cmdArgs := append([]string{"fetch", remoteName}, args...)
+	cmd := exec.Command(GIT, cmdArgs...)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fail to fetch %s: %s: %s", remoteName, err, out)
+	}
+	return nil
+}
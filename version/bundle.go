@@ -0,0 +1,136 @@
+package version
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alibaba/git-repo-go/path"
+)
+
+// BundleEntry describes one bundle listed in a server's clone-bundle index.
+type BundleEntry struct {
+	Name    string    `json:"name"`
+	URI     string    `json:"uri"`
+	Created time.Time `json:"creationTime"`
+}
+
+// appliedBundlesFile records which bundle names have already been unbundled
+// into this repository, so re-syncing does not redo the work.
+func (v Repository) appliedBundlesFile() string {
+	return filepath.Join(v.RepoDir(), "clone-bundle-applied")
+}
+
+// FetchBundleIndex downloads and parses the clone-bundle index published at
+// baseURL, returning the bundles listed there in server order.
+func (v *Repository) FetchBundleIndex(baseURL string) ([]BundleEntry, error) {
+	url := strings.TrimSuffix(baseURL, "/") + "/clone.bundle.index"
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fail to fetch bundle index: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fail to fetch bundle index: %s: %s", url, resp.Status)
+	}
+
+	var entries []BundleEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("fail to parse bundle index: %s", err)
+	}
+	return entries, nil
+}
+
+// ApplyBundleIndex unbundles each entry, oldest first, skipping bundles that
+// were already applied in a previous sync.
+func (v *Repository) ApplyBundleIndex(entries []BundleEntry) error {
+	applied, err := v.loadAppliedBundles()
+	if err != nil {
+		return err
+	}
+
+	sorted := make([]BundleEntry, len(entries))
+	copy(sorted, entries)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j].Created.Before(sorted[j-1].Created); j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+
+	for _, e := range sorted {
+		if _, ok := applied[e.Name]; ok {
+			continue
+		}
+		if err := v.unbundle(e.URI); err != nil {
+			return fmt.Errorf("fail to apply bundle %s: %s", e.Name, err)
+		}
+		applied[e.Name] = struct{}{}
+		if err := v.saveAppliedBundle(e.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v Repository) loadAppliedBundles() (map[string]struct{}, error) {
+	applied := make(map[string]struct{})
+
+	f := v.appliedBundlesFile()
+	if !path.IsFile(f) {
+		return applied, nil
+	}
+	fh, err := os.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		name := strings.TrimSpace(s.Text())
+		if name != "" {
+			applied[name] = struct{}{}
+		}
+	}
+	return applied, s.Err()
+}
+
+func (v Repository) saveAppliedBundle(name string) error {
+	f, err := os.OpenFile(v.appliedBundlesFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(name + "\n")
+	return err
+}
+
+// unbundle downloads the bundle at uri into GitDir and applies it with
+// `git bundle unbundle`, so its refs and objects land in this repository.
+func (v Repository) unbundle(uri string) error {
+	bundlePath := filepath.Join(v.GitDir, "clone-bundle-index.bundle")
+	defer os.Remove(bundlePath)
+
+	ok, err := downloadCloneBundle(uri, bundlePath)
+	if err != nil {
+		return fmt.Errorf("fail to download bundle %s: %s", uri, err)
+	}
+	if !ok {
+		return fmt.Errorf("bundle %s is missing or empty", uri)
+	}
+
+	cmd := exec.Command(GIT, "bundle", "unbundle", bundlePath)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to unbundle %s: %s: %s", uri, err, out)
+	}
+	return nil
+}
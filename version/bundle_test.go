@@ -0,0 +1,79 @@
+package version
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// runGit runs git with args in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %s: %s", args, err, out)
+	}
+}
+
+func TestApplyBundleIndexTwoEntries(t *testing.T) {
+	src := t.TempDir()
+	runGit(t, src, "init")
+	runGit(t, src, "config", "user.email", "test@example.com")
+	runGit(t, src, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("first"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, src, "add", "a.txt")
+	runGit(t, src, "commit", "-m", "first")
+
+	bundleDir := t.TempDir()
+	firstBundle := filepath.Join(bundleDir, "first.bundle")
+	runGit(t, src, "bundle", "create", firstBundle, "--all")
+
+	if err := os.WriteFile(filepath.Join(src, "b.txt"), []byte("second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, src, "add", "b.txt")
+	runGit(t, src, "commit", "-m", "second")
+
+	secondBundle := filepath.Join(bundleDir, "second.bundle")
+	runGit(t, src, "bundle", "create", secondBundle, "--all")
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(bundleDir)))
+	defer srv.Close()
+
+	dst := t.TempDir()
+	runGit(t, dst, "init", "--bare")
+
+	repo := Repository{GitDir: dst}
+
+	entries := []BundleEntry{
+		{Name: "second", URI: srv.URL + "/second.bundle", Created: time.Unix(200, 0)},
+		{Name: "first", URI: srv.URL + "/first.bundle", Created: time.Unix(100, 0)},
+	}
+
+	if err := repo.ApplyBundleIndex(entries); err != nil {
+		t.Fatalf("ApplyBundleIndex: %s", err)
+	}
+
+	applied, err := repo.loadAppliedBundles()
+	if err != nil {
+		t.Fatalf("loadAppliedBundles: %s", err)
+	}
+	for _, name := range []string{"first", "second"} {
+		if _, ok := applied[name]; !ok {
+			t.Errorf("expected bundle %q to be recorded as applied", name)
+		}
+	}
+
+	// Re-applying the same index must not re-download or re-unbundle
+	// already-applied entries.
+	if err := repo.ApplyBundleIndex(entries); err != nil {
+		t.Fatalf("ApplyBundleIndex (second pass): %s", err)
+	}
+}
@@ -0,0 +1,23 @@
+package version
+
+import "fmt"
+
+// RecentSubjects returns the commit subjects of the most recent n commits
+// reachable from ref, most recent first. If ref has fewer than n commits,
+// it returns all of them without error.
+func (v Repository) RecentSubjects(ref string, n int) ([]string, error) {
+	shas, err := v.Revlist(fmt.Sprintf("-%d", n), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := make([]string, 0, len(shas))
+	for _, sha := range shas {
+		info, err := v.commitInfo(sha)
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, info.Subject)
+	}
+	return subjects, nil
+}
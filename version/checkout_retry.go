@@ -0,0 +1,36 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// writeWorktreeFileWithRetry writes data to path, retrying up to
+// RepoSettings.CheckoutRetries times with linear backoff when the write
+// fails with a transient EBUSY or EACCES error, such as a file lock held by
+// antivirus or another process during checkout.
+func (v Repository) writeWorktreeFileWithRetry(path string, data []byte, perm os.FileMode) error {
+	retries := 0
+	if v.Settings != nil {
+		retries = v.Settings.CheckoutRetries
+	}
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		err = os.WriteFile(path, data, perm)
+		if err == nil {
+			return nil
+		}
+		if !isTransientCheckoutError(err) {
+			return err
+		}
+		time.Sleep(time.Duration(attempt+1) * 50 * time.Millisecond)
+	}
+	return err
+}
+
+func isTransientCheckoutError(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.EACCES)
+}
@@ -0,0 +1,53 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/alibaba/git-repo-go/common"
+)
+
+// ErrWorkTreeDirty is returned by CheckoutRevision instead of clobbering a
+// worktree that has local modifications.
+var ErrWorkTreeDirty = errors.New("worktree has uncommitted changes")
+
+// CheckoutRevision checks out revision using the semantics appropriate to
+// what it names: a mutable branch is checked out (creating a local
+// tracking branch named after DefaultTrackingBranch if it doesn't exist
+// yet), while a tag or immutable SHA is checked out detached. It refuses to
+// run against a dirty worktree, returning ErrWorkTreeDirty.
+func (v *Repository) CheckoutRevision(revision string) error {
+	if !v.RevisionIsValid(revision) {
+		return fmt.Errorf("fail to resolve revision %s in %s", revision, v.RepoDir())
+	}
+
+	dirty, err := v.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return ErrWorkTreeDirty
+	}
+
+	if common.IsImmutable(revision) || v.RevisionIsValid("refs/tags/"+revision) {
+		cmd := exec.Command(GIT, "checkout", "--detach", revision)
+		cmd.Dir = v.RepoDir()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("fail to checkout %s: %s: %s", revision, err, out)
+		}
+		return nil
+	}
+
+	branch := v.DefaultTrackingBranch()
+	if branch == "" {
+		branch = revision
+	}
+
+	cmd := exec.Command(GIT, "checkout", "-B", branch, revision)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to checkout %s onto branch %s: %s: %s", revision, branch, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package version
+
+import "strconv"
+
+// ResolveCloneDepth returns the effective clone depth for this project's
+// fetch, applying precedence: the manifest project's own clone-depth
+// attribute wins when set (even when explicitly "0", meaning unlimited),
+// otherwise globalDepth applies, otherwise 0 (unlimited).
+func (v Repository) ResolveCloneDepth(globalDepth int) int {
+	if v.CloneDepth != "" {
+		if depth, err := strconv.Atoi(v.CloneDepth); err == nil {
+			return depth
+		}
+	}
+	return globalDepth
+}
@@ -0,0 +1,86 @@
+package version
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+// GraphNode is one commit in a CommitGraph result.
+type GraphNode struct {
+	SHA     string
+	Parents []string
+	Refs    []string
+}
+
+// GraphData is a commit graph suitable for driving an ASCII or graphical
+// log view.
+type GraphData struct {
+	Nodes []GraphNode
+}
+
+// CommitGraph walks the history reachable from refs (via native go-git
+// revlist with --parents semantics), up to limit commits total, and
+// decorates each commit with the refs that point directly at it.
+func (v Repository) CommitGraph(refs []string, limit int) (*GraphData, error) {
+	raw := v.Raw()
+	if raw == nil {
+		return nil, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+
+	refsAt := make(map[string][]string)
+	allRefs, err := raw.References()
+	if err == nil {
+		allRefs.ForEach(func(ref *plumbing.Reference) error {
+			if ref.Type() == plumbing.HashReference {
+				sha := ref.Hash().String()
+				refsAt[sha] = append(refsAt[sha], ref.Name().String())
+			}
+			return nil
+		})
+		allRefs.Close()
+	}
+
+	seen := make(map[string]bool)
+	var nodes []GraphNode
+	for _, ref := range refs {
+		if limit > 0 && len(nodes) >= limit {
+			break
+		}
+		hash, err := raw.ResolveRevision(plumbing.Revision(ref))
+		if err != nil {
+			return nil, fmt.Errorf("fail to resolve %s: %s", ref, err)
+		}
+
+		iter, err := raw.Log(&git.LogOptions{From: *hash})
+		if err != nil {
+			return nil, fmt.Errorf("fail to walk history from %s: %s", ref, err)
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			sha := c.Hash.String()
+			if seen[sha] {
+				return nil
+			}
+			seen[sha] = true
+			if limit > 0 && len(nodes) >= limit {
+				return storer.ErrStop
+			}
+
+			var parents []string
+			for _, p := range c.ParentHashes {
+				parents = append(parents, p.String())
+			}
+			nodes = append(nodes, GraphNode{SHA: sha, Parents: parents, Refs: refsAt[sha]})
+			return nil
+		})
+		iter.Close()
+		if err != nil && err != storer.ErrStop {
+			return nil, err
+		}
+	}
+
+	return &GraphData{Nodes: nodes}, nil
+}
@@ -0,0 +1,91 @@
+package version
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ValidateConnectivity does a fast integrity check of revision (or HEAD, if
+// revision is empty): every object reachable from it must be present in the
+// object store. It stops at the first missing object rather than running a
+// full fsck.
+func (v Repository) ValidateConnectivity(revision string) error {
+	if revision == "" {
+		revision = "HEAD"
+	}
+
+	revList := exec.Command(GIT, "rev-list", "--objects", revision)
+	revList.Dir = v.RepoDir()
+	var revListErr bytes.Buffer
+	revList.Stderr = &revListErr
+	objOut, err := revList.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := revList.Start(); err != nil {
+		return err
+	}
+
+	catFile := exec.Command(GIT, "cat-file", "--batch-check")
+	catFile.Dir = v.RepoDir()
+	catFile.Stdin = &shaOnlyReader{bufio.NewScanner(objOut)}
+	out, err := catFile.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := catFile.Start(); err != nil {
+		return err
+	}
+
+	var missing string
+	s := bufio.NewScanner(out)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasSuffix(line, "missing") {
+			missing = strings.Fields(line)[0]
+			break
+		}
+	}
+
+	catFile.Wait()
+	revListWaitErr := revList.Wait()
+
+	if missing != "" {
+		return fmt.Errorf("object %s is missing from the object store", missing)
+	}
+	if revListWaitErr != nil {
+		// rev-list stops walking history as soon as it hits a missing
+		// object, so the traversal itself can fail before cat-file ever
+		// sees that object's sha.
+		if msg := strings.TrimSpace(revListErr.String()); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return fmt.Errorf("git rev-list --objects %s: %s", revision, revListWaitErr)
+	}
+	return nil
+}
+
+// shaOnlyReader adapts `git rev-list --objects` output (sha plus optional
+// path) into the sha-only input `git cat-file --batch-check` expects.
+type shaOnlyReader struct {
+	s *bufio.Scanner
+}
+
+func (r *shaOnlyReader) Read(p []byte) (int, error) {
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+	line := strings.Fields(r.s.Text())
+	if len(line) == 0 {
+		return 0, nil
+	}
+	n := copy(p, line[0]+"\n")
+	return n, nil
+}
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateConnectivityHealthyRepo(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-m", "initial")
+
+	repo := &Repository{GitDir: filepath.Join(dir, ".git")}
+	if err := repo.ValidateConnectivity(""); err != nil {
+		t.Errorf("ValidateConnectivity: %s", err)
+	}
+}
+
+func TestValidateConnectivityMissingObject(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "a.txt")
+	runGit(t, dir, "commit", "-m", "initial")
+
+	out, err := exec.Command(GIT, "-C", dir, "rev-parse", "HEAD:a.txt").Output()
+	if err != nil {
+		t.Fatalf("rev-parse: %s", err)
+	}
+	blobSHA := strings.TrimSpace(string(out))
+
+	objectPath := filepath.Join(dir, ".git", "objects", blobSHA[:2], blobSHA[2:])
+	if err := os.Remove(objectPath); err != nil {
+		t.Fatalf("remove loose object %s: %s", objectPath, err)
+	}
+
+	repo := &Repository{GitDir: filepath.Join(dir, ".git")}
+	err = repo.ValidateConnectivity("")
+	if err == nil {
+		t.Fatal("expected an error for a missing object")
+	}
+	if !strings.Contains(err.Error(), blobSHA) {
+		t.Errorf("error %q does not mention the missing object %s", err, blobSHA)
+	}
+}
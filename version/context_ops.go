@@ -0,0 +1,81 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// FetchContext fetches remote with a git process started through
+// exec.CommandContext, so a cancelled or timed-out ctx actually kills and
+// reaps the child process instead of leaving a stuck fetch to run to
+// completion in the background; the returned error then wraps ctx.Err().
+// It honours opts.MinFreeBytes/OnLowDisk (LowDiskWarn proceeds without
+// logging), opts.Depth, and opts.PruneExpire (as a plain --prune). Unlike
+// Fetch, it does not apply RepoSettings Depth or CloneFilter, set
+// gc.pruneExpire, report progress, or classify auth and timeout failures:
+// every other failure is a FetchError of kind FetchErrorOther.
+func (v *Repository) FetchContext(ctx context.Context, remote string, opts FetchOptions) error {
+	if opts.MinFreeBytes > 0 && opts.OnLowDisk != LowDiskProceed {
+		ok, err := v.HasEnoughDisk(opts.MinFreeBytes)
+		if err != nil {
+			return &FetchError{Kind: FetchErrorOther, Err: err}
+		}
+		if !ok && opts.OnLowDisk != LowDiskWarn {
+			return &FetchError{Kind: FetchErrorOther, Err: ErrInsufficientDisk}
+		}
+	}
+
+	args := []string{"fetch", remote}
+	if opts.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+	if opts.PruneExpire != "" {
+		args = append(args, "--prune")
+	}
+
+	cmd := exec.CommandContext(ctx, GIT, args...)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("fetch %s in %s: %w", remote, v.RepoDir(), ctx.Err())
+		}
+		return &FetchError{Kind: FetchErrorOther, Err: fmt.Errorf("fail to fetch %s: %s: %s", remote, err, out)}
+	}
+	return nil
+}
+
+// RevlistContext works like Revlist, but the underlying `git` process (when
+// the exec fallback is used) is started with CommandContext, so a
+// cancelled or timed-out ctx actually kills the child process instead of
+// leaving it to finish on its own.
+func (v Repository) RevlistContext(ctx context.Context, args ...string) ([]string, error) {
+	if shas, isCount, ok := v.revlistNative(args); ok {
+		if isCount {
+			return []string{strconv.Itoa(len(shas))}, nil
+		}
+		return shas, nil
+	}
+
+	cmdArgs := append([]string{"rev-list"}, args...)
+	cmd := exec.CommandContext(ctx, GIT, cmdArgs...)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("rev-list in %s: %w", v.RepoDir(), ctx.Err())
+		}
+		return nil, fmt.Errorf("fail to rev-list in %s: %s", v.RepoDir(), err)
+	}
+
+	var shas []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			shas = append(shas, line)
+		}
+	}
+	return shas, nil
+}
@@ -0,0 +1,33 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RequiresCredentials performs an unauthenticated capability probe against
+// remoteName (`git ls-remote` with credential prompting disabled) and
+// reports whether the remote demanded credentials, so callers can
+// pre-acquire tokens instead of hitting an interactive prompt mid-fetch.
+func (v Repository) RequiresCredentials(remoteName string) (bool, error) {
+	cmd := exec.Command(GIT, "ls-remote", remoteName, "HEAD")
+	cmd.Dir = v.RepoDir()
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return false, nil
+	}
+
+	msg := strings.ToLower(string(out))
+	switch {
+	case strings.Contains(msg, "401"),
+		strings.Contains(msg, "authentication failed"),
+		strings.Contains(msg, "permission denied"),
+		strings.Contains(msg, "could not read username"),
+		strings.Contains(msg, "publickey"):
+		return true, nil
+	}
+	return false, err
+}
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// ErrCannotDeleteCurrentBranch is returned by DeleteBranch when name is the
+// currently checked-out branch.
+var ErrCannotDeleteCurrentBranch = errors.New("cannot delete the currently checked-out branch")
+
+// DeleteBranch deletes local branch name. It refuses to delete the
+// currently checked-out branch, and without force it refuses to delete a
+// branch that hasn't been merged into its tracking branch.
+func (v *Repository) DeleteBranch(name string, force bool) error {
+	if head := v.GetHead(); head == "refs/heads/"+name || head == name {
+		return ErrCannotDeleteCurrentBranch
+	}
+
+	if !force {
+		merged, err := v.branchIsMerged(name)
+		if err != nil {
+			return err
+		}
+		if !merged {
+			return fmt.Errorf("branch %s is not fully merged into its tracking branch (use force)", name)
+		}
+	}
+
+	raw := v.Raw()
+	if raw == nil {
+		return fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+	refName := plumbing.NewBranchReferenceName(name)
+	if err := raw.Storer.RemoveReference(refName); err != nil {
+		return fmt.Errorf("fail to delete branch %s: %s", name, err)
+	}
+	return nil
+}
+
+func (v Repository) branchIsMerged(name string) (bool, error) {
+	upstream := v.branchUpstream(name)
+	if upstream == "" {
+		upstream = "refs/remotes/origin/" + name
+	}
+	cmd := exec.Command(GIT, "merge-base", "--is-ancestor", name, upstream)
+	cmd.Dir = v.RepoDir()
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("fail to check whether %s is merged: %s", name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,29 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// withDepthArgs injects --depth=N and a refspec for the manifest-pinned
+// revision when RepoSettings.Depth is set and the caller didn't already
+// pass a --depth argument of their own. Fetching the specific revision
+// ref, rather than assuming refs/heads/*, ensures a tag referenced by
+// Revision still resolves after a shallow fetch. A later Fetch call with a
+// larger Depth deepens the existing shallow clone.
+func (v Repository) withDepthArgs(args []string) []string {
+	if v.Settings == nil || v.Settings.Depth <= 0 {
+		return args
+	}
+	for _, a := range args {
+		if a == "--depth" || strings.HasPrefix(a, "--depth=") {
+			return args
+		}
+	}
+
+	out := append([]string{fmt.Sprintf("--depth=%d", v.Settings.Depth)}, args...)
+	if v.Revision != "" {
+		out = append(out, v.Revision)
+	}
+	return out
+}
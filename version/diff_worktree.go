@@ -0,0 +1,39 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// DiffOptions controls DiffWorktree's output.
+type DiffOptions struct {
+	NameOnly bool
+	Paths    []string
+}
+
+// DiffWorktree diffs the working tree against revision, so pre-upload
+// review can see what changed since the last commit rather than only
+// commit-vs-commit history.
+func (v Repository) DiffWorktree(revision string, opts DiffOptions) (string, error) {
+	if v.IsBare {
+		return "", fmt.Errorf("cannot diff worktree of a bare repository")
+	}
+
+	args := []string{"diff"}
+	if opts.NameOnly {
+		args = append(args, "--name-only")
+	}
+	args = append(args, revision)
+	if len(opts.Paths) > 0 {
+		args = append(args, "--")
+		args = append(args, opts.Paths...)
+	}
+
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("fail to diff worktree against %s: %s", revision, err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,29 @@
+package version
+
+// WorkTreeStatus reports how many entries in the worktree are staged,
+// unstaged, or untracked, per StatusCounts.
+type WorkTreeStatus struct {
+	Staged    int
+	Unstaged  int
+	Untracked int
+}
+
+// WorkTreeStatus computes a WorkTreeStatus for the repository's worktree.
+// It returns ErrBareWorktree for a bare repository.
+func (v Repository) WorkTreeStatus() (*WorkTreeStatus, error) {
+	staged, unstaged, untracked, _, err := v.StatusCounts()
+	if err != nil {
+		return nil, err
+	}
+	return &WorkTreeStatus{Staged: staged, Unstaged: unstaged, Untracked: untracked}, nil
+}
+
+// IsDirty reports whether the worktree has any staged, unstaged, or
+// untracked changes.
+func (v Repository) IsDirty() (bool, error) {
+	status, err := v.WorkTreeStatus()
+	if err != nil {
+		return false, err
+	}
+	return status.Staged > 0 || status.Unstaged > 0 || status.Untracked > 0, nil
+}
@@ -0,0 +1,21 @@
+package version
+
+import (
+	"errors"
+	"syscall"
+)
+
+// ErrInsufficientDisk is returned when a fetch is aborted because the
+// target filesystem does not have requiredBytes free.
+var ErrInsufficientDisk = errors.New("insufficient disk space")
+
+// HasEnoughDisk reports whether the filesystem backing the repository has
+// at least requiredBytes free.
+func (v Repository) HasEnoughDisk(requiredBytes int64) (bool, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(v.RepoDir(), &stat); err != nil {
+		return false, err
+	}
+	available := int64(stat.Bavail) * int64(stat.Bsize)
+	return available >= requiredBytes, nil
+}
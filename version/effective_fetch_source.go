@@ -0,0 +1,22 @@
+package version
+
+// EffectiveFetchSource reports where a fetch of remoteName would actually
+// pull objects from: when v.Reference names an alternate that already has
+// the objects needed to satisfy the fetch (i.e. CheckAlternates passes and
+// the reference is set), the network round-trip is largely a no-op for
+// history it already shares, so viaReference is true. Otherwise objects
+// come straight from the configured remote URL.
+func (v Repository) EffectiveFetchSource(remoteName string) (url string, viaReference bool, err error) {
+	url = v.GitConfigRemoteURL(remoteName)
+	if url == "" {
+		url = v.RemoteURL
+	}
+
+	if v.Reference == "" || !v.HasAlternates() {
+		return url, false, nil
+	}
+	if err := v.CheckAlternates(); err != nil {
+		return url, false, nil
+	}
+	return url, true, nil
+}
@@ -0,0 +1,27 @@
+package version
+
+import "fmt"
+
+// FetchAllRemotes fetches every remote configured in v.Remotes with opts,
+// so a project pinned to more than one remote (e.g. a fork plus its
+// upstream) stays in sync in one call. It aggregates errors instead of
+// stopping at the first one, so a single unreachable remote does not
+// prevent the others from updating.
+func (v *Repository) FetchAllRemotes(opts FetchOptions) error {
+	if v.Remotes == nil {
+		return nil
+	}
+
+	var failures []string
+	v.Remotes.ForEach(func(remote *RemoteConfig) error {
+		if err := v.Fetch(remote.Name, opts); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", remote.Name, err))
+		}
+		return nil
+	})
+
+	if len(failures) > 0 {
+		return fmt.Errorf("fail to fetch %d remote(s): %s", len(failures), failures)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package version
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	log "github.com/jiangxin/multi-log"
+)
+
+// FetchOptions configures a Fetch call.
+type FetchOptions struct {
+	// Progress, if set, receives git's raw progress output as it streams.
+	Progress io.Writer
+
+	// ProgressFunc, if set, is called as object counts are parsed out of
+	// the progress stream, with received and total object counts.
+	ProgressFunc func(received, total int)
+
+	// Depth and CloneFilter override RepoSettings.Depth/CloneFilter for
+	// this call when non-zero/non-empty.
+	Depth       int
+	CloneFilter string
+
+	// PruneExpire, if set, enables --prune and sets gc.pruneExpire for the
+	// duration of the fetch (e.g. "now", "2.weeks.ago"), so mirrors can
+	// reclaim space from pruned objects immediately instead of waiting out
+	// git's default two-week grace period.
+	PruneExpire string
+
+	// MinFreeBytes, if non-zero, is checked against HasEnoughDisk before
+	// the fetch starts; OnLowDisk controls what happens when it's not met.
+	MinFreeBytes int64
+	OnLowDisk    LowDiskPolicy
+}
+
+// LowDiskPolicy controls how Fetch reacts when HasEnoughDisk reports
+// insufficient space for FetchOptions.MinFreeBytes.
+type LowDiskPolicy int
+
+const (
+	// LowDiskAbort returns ErrInsufficientDisk without fetching.
+	LowDiskAbort LowDiskPolicy = iota
+	// LowDiskWarn logs a warning and proceeds with the fetch anyway.
+	LowDiskWarn
+	// LowDiskProceed ignores the disk check entirely.
+	LowDiskProceed
+)
+
+// FetchErrorKind classifies why a Fetch failed, so callers can decide
+// whether retrying makes sense.
+type FetchErrorKind int
+
+const (
+	// FetchErrorOther is an error that isn't recognized as auth or
+	// network-timeout related.
+	FetchErrorOther FetchErrorKind = iota
+	// FetchErrorAuth means the remote rejected our credentials; retrying
+	// without acquiring new credentials will not help.
+	FetchErrorAuth
+	// FetchErrorTimeout means the transfer did not complete in time and
+	// may succeed on retry.
+	FetchErrorTimeout
+)
+
+// FetchError wraps a Fetch failure with its classification.
+type FetchError struct {
+	Kind FetchErrorKind
+	Err  error
+}
+
+func (e *FetchError) Error() string { return e.Err.Error() }
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// Fetch fetches remote into the repository, reporting progress through
+// opts.Progress/opts.ProgressFunc as objects are received. It honors
+// RepoSettings.Depth and RepoSettings.CloneFilter unless opts overrides
+// them. On failure it returns a *FetchError distinguishing auth failures
+// from network timeouts, so the sync loop can decide whether to retry.
+func (v *Repository) Fetch(remote string, opts FetchOptions) error {
+	if opts.MinFreeBytes > 0 && opts.OnLowDisk != LowDiskProceed {
+		ok, err := v.HasEnoughDisk(opts.MinFreeBytes)
+		if err != nil {
+			return &FetchError{Kind: FetchErrorOther, Err: err}
+		}
+		if !ok {
+			switch opts.OnLowDisk {
+			case LowDiskWarn:
+				log.Warnf("fetch %s: low disk space, proceeding anyway", remote)
+			default:
+				return &FetchError{Kind: FetchErrorOther, Err: ErrInsufficientDisk}
+			}
+		}
+	}
+
+	depth := opts.Depth
+	if depth == 0 && v.Settings != nil {
+		depth = v.Settings.Depth
+	}
+	filter := opts.CloneFilter
+	if filter == "" && v.Settings != nil {
+		filter = v.Settings.CloneFilter
+	}
+
+	args := []string{"--progress"}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	if filter != "" {
+		ok, err := v.remoteAdvertisesFilter(remote)
+		if err != nil {
+			return &FetchError{Kind: FetchErrorOther, Err: err}
+		}
+		if !ok {
+			return &FetchError{Kind: FetchErrorOther, Err: fmt.Errorf("remote %s does not advertise the partial clone filter capability", remote)}
+		}
+		args = append(args, "--filter="+filter)
+	}
+
+	if opts.PruneExpire != "" {
+		args = append(args, "--prune")
+	}
+
+	cmdArgs := []string{}
+	if opts.PruneExpire != "" {
+		cmdArgs = append(cmdArgs, "-c", "gc.pruneExpire="+opts.PruneExpire)
+	}
+	cmdArgs = append(cmdArgs, "fetch", remote)
+	cmdArgs = append(cmdArgs, args...)
+	cmd := exec.Command(GIT, cmdArgs...)
+	cmd.Dir = v.RepoDir()
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return &FetchError{Kind: FetchErrorOther, Err: err}
+	}
+	if err := cmd.Start(); err != nil {
+		return &FetchError{Kind: FetchErrorOther, Err: err}
+	}
+
+	var lastLine string
+	buf := make([]byte, 4096)
+	for {
+		n, readErr := stderr.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+			if opts.Progress != nil {
+				opts.Progress.Write(chunk)
+			}
+			lastLine = lastNonEmptyLine(string(chunk))
+			if opts.ProgressFunc != nil {
+				if received, total, ok := parseObjectProgress(lastLine); ok {
+					opts.ProgressFunc(received, total)
+				}
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return classifyFetchError(err, lastLine)
+	}
+	return nil
+}
+
+func lastNonEmptyLine(s string) string {
+	lines := strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == '\r' })
+	if len(lines) == 0 {
+		return ""
+	}
+	return lines[len(lines)-1]
+}
+
+// parseObjectProgress extracts "received/total" counts out of a line like
+// "Receiving objects:  42% (420/1000)".
+func parseObjectProgress(line string) (received, total int, ok bool) {
+	open := strings.LastIndex(line, "(")
+	shut := strings.LastIndex(line, ")")
+	if open < 0 || shut < open {
+		return 0, 0, false
+	}
+	fields := strings.SplitN(line[open+1:shut], "/", 2)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	received, err1 := strconv.Atoi(strings.TrimSpace(fields[0]))
+	total, err2 := strconv.Atoi(strings.TrimSpace(fields[1]))
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return received, total, true
+}
+
+func classifyFetchError(err error, lastLine string) error {
+	msg := strings.ToLower(lastLine + " " + err.Error())
+	switch {
+	case strings.Contains(msg, "authentication"), strings.Contains(msg, "401"), strings.Contains(msg, "permission denied"):
+		return &FetchError{Kind: FetchErrorAuth, Err: err}
+	case strings.Contains(msg, "timed out"), strings.Contains(msg, "timeout"), strings.Contains(msg, "connection reset"):
+		return &FetchError{Kind: FetchErrorTimeout, Err: err}
+	default:
+		return &FetchError{Kind: FetchErrorOther, Err: err}
+	}
+}
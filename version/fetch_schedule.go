@@ -0,0 +1,50 @@
+package version
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ComputeFetchSchedule groups repos into sequential batches such that no
+// batch issues more than perHostLimit concurrent fetches against any single
+// remote host. Callers run one batch at a time through a worker pool,
+// fetching everything in a batch concurrently.
+func ComputeFetchSchedule(repos []*Repository, perHostLimit int) [][]*Repository {
+	if perHostLimit <= 0 {
+		perHostLimit = 1
+	}
+
+	var schedule [][]*Repository
+	hostCounts := map[string]int{}
+
+	for _, r := range repos {
+		host := remoteHost(r.RemoteURL)
+
+		placed := false
+		for i := range schedule {
+			if hostCounts[batchKey(i, host)] < perHostLimit {
+				schedule[i] = append(schedule[i], r)
+				hostCounts[batchKey(i, host)]++
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			schedule = append(schedule, []*Repository{r})
+			hostCounts[batchKey(len(schedule)-1, host)] = 1
+		}
+	}
+	return schedule
+}
+
+func batchKey(batch int, host string) string {
+	return fmt.Sprintf("%s#%d", host, batch)
+}
+
+func remoteHost(remoteURL string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Host == "" {
+		return remoteURL
+	}
+	return u.Host
+}
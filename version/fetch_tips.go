@@ -0,0 +1,83 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alibaba/git-repo-go/file"
+	"github.com/alibaba/git-repo-go/path"
+)
+
+// fetchTipsFile records the remote ref tips observed at the last fetch, so
+// a subsequent sync can skip fetching when nothing has changed.
+func (v Repository) fetchTipsFile() string {
+	return filepath.Join(v.GitDir, "info", "fetch-tips")
+}
+
+// LastFetchedTips returns the remote ref tips (ref -> sha) recorded at the
+// last successful fetch, or an empty map if none have been recorded yet.
+func (v *Repository) LastFetchedTips() (map[string]string, error) {
+	tips := make(map[string]string)
+
+	f := v.fetchTipsFile()
+	if !path.IsFile(f) {
+		return tips, nil
+	}
+	fh, err := os.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		tips[fields[0]] = fields[1]
+	}
+	return tips, s.Err()
+}
+
+// RecordFetchedTips persists tips as the new fetch record.
+func (v *Repository) RecordFetchedTips(tips map[string]string) error {
+	f := v.fetchTipsFile()
+	os.MkdirAll(filepath.Dir(f), 0755)
+
+	fh, err := file.New(f).OpenCreateRewrite()
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	for ref, sha := range tips {
+		if _, err := fmt.Fprintf(fh, "%s %s\n", ref, sha); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NeedsFetch compares the remote's current tips against the last recorded
+// fetch and reports whether a fetch would bring in anything new.
+func (v *Repository) NeedsFetch(remoteName string) (bool, error) {
+	current, err := v.remoteRefs(remoteName)
+	if err != nil {
+		return true, err
+	}
+	last, err := v.LastFetchedTips()
+	if err != nil {
+		return true, err
+	}
+
+	for ref, sha := range current {
+		if last[ref] != sha {
+			return true, nil
+		}
+	}
+	return false, nil
+}
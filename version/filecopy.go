@@ -0,0 +1,20 @@
+package version
+
+// FileOp describes one manifest-declared copyfile or linkfile source/dest
+// pair, before it is applied to the worktree.
+type FileOp struct {
+	Src  string
+	Dest string
+}
+
+// CopyLinkFileList returns the manifest project's declared copyfile and
+// linkfile entries as previewable operations, without applying them.
+func (v Repository) CopyLinkFileList() (copies []FileOp, links []FileOp, err error) {
+	for _, c := range v.CopyFiles {
+		copies = append(copies, FileOp{Src: c.Src, Dest: c.Dest})
+	}
+	for _, l := range v.LinkFiles {
+		links = append(links, FileOp{Src: l.Src, Dest: l.Dest})
+	}
+	return copies, links, nil
+}
@@ -0,0 +1,61 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// GCOptions controls GC's behavior.
+type GCOptions struct {
+	Aggressive bool
+
+	// KeepRefs lists refs whose reachable objects must survive GC even
+	// though nothing else references them, e.g. objects backing an
+	// in-flight upload. Each is protected via a temporary keep ref for
+	// the duration of the GC run.
+	KeepRefs []string
+}
+
+// GC runs `git gc` with opts applied, temporarily pinning opts.KeepRefs so
+// aggressive pruning cannot collect objects still needed by in-flight work.
+func (v Repository) GC(opts GCOptions) error {
+	tempRefs, err := v.pinKeepRefs(opts.KeepRefs)
+	if err != nil {
+		return err
+	}
+	defer v.unpinKeepRefs(tempRefs)
+
+	args := []string{"gc"}
+	if opts.Aggressive {
+		args = append(args, "--aggressive")
+	}
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to gc: %s: %s", err, out)
+	}
+	return nil
+}
+
+func (v Repository) pinKeepRefs(refs []string) ([]string, error) {
+	var created []string
+	for i, ref := range refs {
+		keepRef := fmt.Sprintf("refs/keep/gc-%d", i)
+		cmd := exec.Command(GIT, "update-ref", keepRef, ref)
+		cmd.Dir = v.RepoDir()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			v.unpinKeepRefs(created)
+			return nil, fmt.Errorf("fail to pin %s: %s: %s", ref, err, out)
+		}
+		created = append(created, keepRef)
+	}
+	return created, nil
+}
+
+func (v Repository) unpinKeepRefs(refs []string) {
+	for _, ref := range refs {
+		cmd := exec.Command(GIT, "update-ref", "-d", ref)
+		cmd.Dir = v.RepoDir()
+		cmd.Run()
+	}
+}
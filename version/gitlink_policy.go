@@ -0,0 +1,83 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// ApplyGitlinkPolicy walks the tree at revision, and for every gitlink
+// (submodule) entry applies RepoSettings.GitlinkPolicy: GitlinkEmpty
+// leaves whatever git already checked out, GitlinkSkip removes the
+// placeholder directory, and GitlinkInit initializes and updates the
+// submodule in place. With no Settings, it defaults to GitlinkEmpty (a
+// no-op), matching plain non-recursive `git checkout`.
+func (v Repository) ApplyGitlinkPolicy(revision string) error {
+	policy := GitlinkEmpty
+	if v.Settings != nil {
+		policy = v.Settings.GitlinkPolicy
+	}
+	if policy == GitlinkEmpty {
+		return nil
+	}
+
+	links, err := v.gitlinkPaths(revision)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range links {
+		full := filepath.Join(v.RepoDir(), path)
+		switch policy {
+		case GitlinkSkip:
+			if err := os.RemoveAll(full); err != nil {
+				return fmt.Errorf("fail to remove gitlink placeholder %s: %s", path, err)
+			}
+		case GitlinkInit:
+			cmd := exec.Command(GIT, "submodule", "update", "--init", "--", path)
+			cmd.Dir = v.RepoDir()
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("fail to init gitlink %s: %s: %s", path, err, out)
+			}
+		}
+	}
+	return nil
+}
+
+func (v Repository) gitlinkPaths(revision string) ([]string, error) {
+	raw := v.Raw()
+	if raw == nil {
+		return nil, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+	hash, err := raw.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("fail to resolve %s: %s", revision, err)
+	}
+	commit, err := raw.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("fail to load commit %s: %s", revision, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var links []string
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err != nil {
+			break
+		}
+		if entry.Mode == filemode.Submodule {
+			links = append(links, name)
+		}
+	}
+	return links, nil
+}
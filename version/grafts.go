@@ -0,0 +1,103 @@
+package version
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/alibaba/git-repo-go/path"
+)
+
+func (v Repository) graftsFile() string {
+	return filepath.Join(v.CommonDir(), "info", "grafts")
+}
+
+// HasStaleGrafts reports whether info/grafts contains entries whose parent
+// rewrite is no longer needed because the real history is now fully
+// present, as can happen after Unshallow or a partial deepen.
+func (v Repository) HasStaleGrafts() (bool, error) {
+	grafts, err := v.readGrafts()
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range grafts {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if v.commitHasRealParents(fields[0]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RemoveStaleGrafts drops graft entries whose referenced commit's real
+// parents are now fully present in the object store.
+func (v Repository) RemoveStaleGrafts() error {
+	grafts, err := v.readGrafts()
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	for _, line := range grafts {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && v.commitHasRealParents(fields[0]) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	if len(kept) == 0 {
+		return os.Remove(v.graftsFile())
+	}
+	return os.WriteFile(v.graftsFile(), []byte(strings.Join(kept, "\n")+"\n"), 0644)
+}
+
+func (v Repository) readGrafts() ([]string, error) {
+	f := v.graftsFile()
+	if !path.IsFile(f) {
+		return nil, nil
+	}
+	fh, err := os.Open(f)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var lines []string
+	s := bufio.NewScanner(fh)
+	for s.Scan() {
+		if s.Text() != "" {
+			lines = append(lines, s.Text())
+		}
+	}
+	return lines, s.Err()
+}
+
+// commitHasRealParents reports whether sha's actual (non-grafted) parents
+// can be resolved from the object store, meaning the graft rewriting them
+// away is no longer necessary.
+func (v Repository) commitHasRealParents(sha string) bool {
+	cmd := exec.Command(GIT, "--no-replace-objects", "cat-file", "-p", sha)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "parent ") {
+			parent := strings.Fields(line)[1]
+			check := exec.Command(GIT, "cat-file", "-e", parent)
+			check.Dir = v.RepoDir()
+			if err := check.Run(); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package version
+
+import "strings"
+
+// GroupSet returns the project's declared manifest groups together with
+// the implicit groups every project belongs to ("all", "name:<project>"
+// and "path:<project-path>"), as a set suitable for O(1) membership
+// tests.
+func (v Repository) GroupSet() map[string]struct{} {
+	fields := strings.Split(v.Groups, ",")
+	set := make(map[string]struct{}, len(fields)+3)
+
+	set["all"] = struct{}{}
+	set["name:"+v.Name] = struct{}{}
+	set["path:"+v.Path] = struct{}{}
+
+	for _, g := range fields {
+		g = strings.TrimSpace(g)
+		if g == "" {
+			continue
+		}
+		set[g] = struct{}{}
+	}
+
+	return set
+}
@@ -0,0 +1,31 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/alibaba/git-repo-go/manifest"
+)
+
+func TestGroupSet(t *testing.T) {
+	repo := Repository{Project: manifest.Project{
+		Name:   "myproject",
+		Path:   "path/to/myproject",
+		Groups: "core, notdefault ,",
+	}}
+
+	set := repo.GroupSet()
+
+	want := []string{"all", "name:myproject", "path:path/to/myproject", "core", "notdefault"}
+	for _, g := range want {
+		if _, ok := set[g]; !ok {
+			t.Errorf("expected group %q in set", g)
+		}
+	}
+
+	if _, ok := set[""]; ok {
+		t.Errorf("empty group from trailing comma should not be included")
+	}
+	if len(set) != len(want) {
+		t.Errorf("GroupSet() = %v, want exactly %v", set, want)
+	}
+}
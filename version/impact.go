@@ -0,0 +1,27 @@
+package version
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ProjectsUnderPath returns the repos among repos whose worktree path is at
+// or under the given path, so callers can answer "which projects own path
+// X" for impact analysis. The top-level project (Path ".") matches every
+// query path.
+func ProjectsUnderPath(repos []*Repository, path string) []*Repository {
+	clean := filepath.Clean(path)
+
+	var matched []*Repository
+	for _, r := range repos {
+		projectPath := filepath.Clean(r.Path)
+		if projectPath == "." {
+			matched = append(matched, r)
+			continue
+		}
+		if projectPath == clean || strings.HasPrefix(clean, projectPath+string(filepath.Separator)) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,24 @@
+package version
+
+import (
+	"net/url"
+
+	log "github.com/jiangxin/multi-log"
+)
+
+// applyInsecureHosts writes http.<url>.sslVerify=false scoped to each host
+// in RepoSettings.InsecureHosts, leaving verification enabled everywhere
+// else, and logs a warning for each host that has verification disabled.
+func (v *Repository) applyInsecureHosts() error {
+	if v.Settings == nil || len(v.Settings.InsecureHosts) == 0 {
+		return nil
+	}
+
+	cfg := v.Config()
+	for _, host := range v.Settings.InsecureHosts {
+		scoped := (&url.URL{Scheme: "https", Host: host}).String()
+		cfg.Set("http."+scoped+".sslVerify", "false")
+		log.Warnf("TLS certificate verification disabled for %s", host)
+	}
+	return v.SaveConfig(cfg)
+}
@@ -0,0 +1,76 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// LargeObject describes a blob in a project's history that is at least as
+// large as the threshold LargeObjects was called with.
+type LargeObject struct {
+	SHA   string
+	Size  int64
+	Paths []string
+}
+
+// LargeObjects walks every object reachable from any ref and returns the blobs
+// whose size is at least minBytes, together with the path(s) they were
+// found under. This is meant to surface candidates for Git LFS migration.
+func (v Repository) LargeObjects(minBytes int64) ([]LargeObject, error) {
+	revList := exec.Command(GIT, "rev-list", "--objects", "--all")
+	revList.Dir = v.RepoDir()
+	objOut, err := revList.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := revList.Start(); err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string][]string)
+	var shas []string
+	s := bufio.NewScanner(objOut)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		sha := fields[0]
+		shas = append(shas, sha)
+		if len(fields) > 1 {
+			paths[sha] = append(paths[sha], strings.Join(fields[1:], " "))
+		}
+	}
+	if err := revList.Wait(); err != nil {
+		return nil, fmt.Errorf("fail to list objects in %s: %s", v.RepoDir(), err)
+	}
+
+	catFile := exec.Command(GIT, "cat-file", "--batch-check=%(objectname) %(objecttype) %(objectsize)")
+	catFile.Dir = v.RepoDir()
+	catFile.Stdin = strings.NewReader(strings.Join(shas, "\n") + "\n")
+	out, err := catFile.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to inspect objects in %s: %s", v.RepoDir(), err)
+	}
+
+	var large []LargeObject
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil || size < minBytes {
+			continue
+		}
+		large = append(large, LargeObject{
+			SHA:   fields[0],
+			Size:  size,
+			Paths: paths[fields[0]],
+		})
+	}
+	return large, nil
+}
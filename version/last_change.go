@@ -0,0 +1,61 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// LastChangeForPaths returns, for each of paths, the most recent commit at
+// or before revision that touched it. It walks history once with `git log
+// --name-only` rather than running one rev-list per path, stopping as soon
+// as every path has been resolved.
+func (v Repository) LastChangeForPaths(revision string, paths []string) (map[string]CommitInfo, error) {
+	remaining := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		remaining[p] = true
+	}
+
+	result := make(map[string]CommitInfo)
+	if len(remaining) == 0 {
+		return result, nil
+	}
+
+	cmd := exec.Command(GIT, "log", "--format=commit %H", "--name-only", revision)
+	cmd.Dir = v.RepoDir()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("fail to walk history of %s: %s", revision, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("fail to walk history of %s: %s", revision, err)
+	}
+
+	var currentSHA string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() && len(remaining) > 0 {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "commit "):
+			currentSHA = strings.TrimPrefix(line, "commit ")
+		case line == "":
+			continue
+		case remaining[line]:
+			delete(remaining, line)
+			info, err := v.commitInfo(currentSHA)
+			if err != nil {
+				_ = cmd.Wait()
+				return nil, err
+			}
+			result[line] = info
+		}
+	}
+
+	// Stop git once every path is resolved instead of letting it walk the
+	// rest of history, then reap it.
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package version
+
+import (
+	"path/filepath"
+)
+
+// LayoutInfo captures a project's on-disk layout with every path expressed
+// relative to a chosen top-level directory, so the layout can be recreated
+// under a different base path on another machine.
+type LayoutInfo struct {
+	WorktreePath string
+	GitDir       string
+	ObjectsDir   string
+	Alternates   []string
+}
+
+// RelativeLayout returns v's worktree, gitdir, objects dir, and alternates
+// expressed relative to topDir.
+func (v Repository) RelativeLayout(topDir string) (*LayoutInfo, error) {
+	rel := func(p string) (string, error) {
+		if p == "" {
+			return "", nil
+		}
+		return filepath.Rel(topDir, p)
+	}
+
+	worktree, err := rel(v.Path)
+	if err != nil {
+		return nil, err
+	}
+	gitDir, err := rel(v.GitDir)
+	if err != nil {
+		return nil, err
+	}
+	objectsDir, err := rel(filepath.Join(v.CommonDir(), "objects"))
+	if err != nil {
+		return nil, err
+	}
+
+	var alternates []string
+	if v.Reference != "" {
+		refRel, err := rel(v.Reference)
+		if err != nil {
+			return nil, err
+		}
+		alternates = append(alternates, refRel)
+	}
+
+	return &LayoutInfo{
+		WorktreePath: worktree,
+		GitDir:       gitDir,
+		ObjectsDir:   objectsDir,
+		Alternates:   alternates,
+	}, nil
+}
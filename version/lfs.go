@@ -0,0 +1,200 @@
+package version
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/v1"
+
+// FetchLFS downloads the Git LFS objects referenced by pointer files at
+// revision, skipping objects already present in .git/lfs/objects. It is a
+// no-op when the repository has no .gitattributes, or when
+// RepoSettings.LFSEnabled is false. It returns the paths whose objects
+// failed to download rather than aborting at the first failure.
+func (v *Repository) FetchLFS(revision string) ([]string, error) {
+	if v.Settings == nil || !v.Settings.LFSEnabled {
+		return nil, nil
+	}
+
+	attrs, err := v.lfsTrackedPaths(revision)
+	if err != nil {
+		return nil, err
+	}
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+
+	var failed []string
+	for _, path := range attrs {
+		oid, size, err := v.readLFSPointer(revision, path)
+		if err != nil {
+			// Not every attribute-matched path is necessarily a pointer
+			// file (e.g. it may predate LFS tracking); skip it.
+			continue
+		}
+		if v.hasLFSObject(oid) {
+			continue
+		}
+		if err := v.downloadLFSObject(oid, size); err != nil {
+			failed = append(failed, path)
+		}
+	}
+	return failed, nil
+}
+
+// lfsTrackedPaths returns the paths at revision whose .gitattributes marks
+// them "filter=lfs".
+func (v Repository) lfsTrackedPaths(revision string) ([]string, error) {
+	catAttrs := exec.Command(GIT, "show", revision+":.gitattributes")
+	catAttrs.Dir = v.RepoDir()
+	out, err := catAttrs.Output()
+	if err != nil {
+		// No .gitattributes at this revision: nothing to fetch.
+		return nil, nil
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.Contains(line, "filter=lfs") {
+			fields := strings.Fields(line)
+			if len(fields) > 0 {
+				patterns = append(patterns, fields[0])
+			}
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	lsTree := exec.Command(GIT, "ls-tree", "-r", "--name-only", revision)
+	lsTree.Dir = v.RepoDir()
+	out, err = lsTree.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list files at %s: %s", revision, err)
+	}
+
+	var matched []string
+	for _, path := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+				matched = append(matched, path)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+func (v Repository) readLFSPointer(revision, path string) (oid string, size int64, err error) {
+	cmd := exec.Command(GIT, "show", revision+":"+path)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", 0, err
+	}
+	if !strings.HasPrefix(string(out), lfsPointerPrefix) {
+		return "", 0, fmt.Errorf("%s is not an LFS pointer file", path)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "oid sha256:") {
+			oid = strings.TrimPrefix(line, "oid sha256:")
+		}
+		if strings.HasPrefix(line, "size ") {
+			size, _ = strconv.ParseInt(strings.TrimPrefix(line, "size "), 10, 64)
+		}
+	}
+	if oid == "" {
+		return "", 0, fmt.Errorf("%s is missing an oid", path)
+	}
+	return oid, size, nil
+}
+
+func (v Repository) lfsObjectPath(oid string) string {
+	if len(oid) < 4 {
+		return filepath.Join(v.CommonDir(), "lfs", "objects", oid)
+	}
+	return filepath.Join(v.CommonDir(), "lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+func (v Repository) hasLFSObject(oid string) bool {
+	_, err := os.Stat(v.lfsObjectPath(oid))
+	return err == nil
+}
+
+type lfsBatchRequest struct {
+	Operation string          `json:"operation"`
+	Transfers []string        `json:"transfers"`
+	Objects   []lfsObjectSpec `json:"objects"`
+}
+
+type lfsObjectSpec struct {
+	Oid  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type lfsBatchResponse struct {
+	Objects []struct {
+		Oid     string `json:"oid"`
+		Actions struct {
+			Download struct {
+				Href string `json:"href"`
+			} `json:"download"`
+		} `json:"actions"`
+	} `json:"objects"`
+}
+
+func (v Repository) downloadLFSObject(oid string, size int64) error {
+	endpoint := strings.TrimSuffix(v.RemoteURL, ".git") + ".git/info/lfs/objects/batch"
+
+	reqBody, err := json.Marshal(lfsBatchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   []lfsObjectSpec{{Oid: oid, Size: size}},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(endpoint, "application/vnd.git-lfs+json", bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("fail to reach LFS endpoint %s: %s", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	var batch lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return fmt.Errorf("fail to parse LFS batch response: %s", err)
+	}
+	if len(batch.Objects) == 0 || batch.Objects[0].Actions.Download.Href == "" {
+		return fmt.Errorf("LFS endpoint has no download action for %s", oid)
+	}
+
+	objResp, err := http.Get(batch.Objects[0].Actions.Download.Href)
+	if err != nil {
+		return fmt.Errorf("fail to download LFS object %s: %s", oid, err)
+	}
+	defer objResp.Body.Close()
+
+	dest := v.lfsObjectPath(oid)
+	os.MkdirAll(filepath.Dir(dest), 0755)
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, objResp.Body); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("fail to save LFS object %s: %s", oid, err)
+	}
+	return nil
+}
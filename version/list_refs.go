@@ -0,0 +1,143 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// Branch describes a local branch and, when it has a configured upstream,
+// how far it has diverged from it.
+type Branch struct {
+	Name     string
+	SHA      string
+	Upstream string
+	Ahead    int
+	Behind   int
+}
+
+// Tag describes a tag, lightweight or annotated.
+type Tag struct {
+	Name      string
+	SHA       string
+	Annotated bool
+	Tagger    string
+}
+
+// ListBranches returns every local branch, with Ahead/Behind computed
+// against its configured upstream via merge-base. A branch with no
+// upstream configured leaves Upstream empty and Ahead/Behind at zero. An
+// unborn repository returns an empty slice, not an error.
+func (v Repository) ListBranches() ([]Branch, error) {
+	if v.isUnborn() {
+		return nil, nil
+	}
+
+	raw := v.Raw()
+	if raw == nil {
+		return nil, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+
+	refs, err := raw.References()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list refs in %s: %s", v.RepoDir(), err)
+	}
+	defer refs.Close()
+
+	var branches []Branch
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsBranch() {
+			return nil
+		}
+		name := ref.Name().Short()
+		b := Branch{Name: name, SHA: ref.Hash().String()}
+
+		if upstream := v.branchUpstream(name); upstream != "" {
+			b.Upstream = upstream
+			ahead, behind, err := v.aheadBehind(name, upstream)
+			if err == nil {
+				b.Ahead, b.Behind = ahead, behind
+			}
+		}
+		branches = append(branches, b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// ListTags returns every tag, resolving annotated tags to their pointed-to
+// commit. An unborn repository returns an empty slice, not an error.
+func (v Repository) ListTags() ([]Tag, error) {
+	if v.isUnborn() {
+		return nil, nil
+	}
+
+	raw := v.Raw()
+	if raw == nil {
+		return nil, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+
+	refs, err := raw.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list tags in %s: %s", v.RepoDir(), err)
+	}
+	defer refs.Close()
+
+	var tags []Tag
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		t := Tag{Name: ref.Name().Short(), SHA: ref.Hash().String()}
+
+		if obj, err := raw.TagObject(ref.Hash()); err == nil {
+			t.Annotated = true
+			t.SHA = obj.Target.String()
+			t.Tagger = fmt.Sprintf("%s <%s>", obj.Tagger.Name, obj.Tagger.Email)
+		}
+		tags = append(tags, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// branchUpstream returns name's configured upstream in remote/branch form,
+// or "" if none is configured.
+func (v Repository) branchUpstream(name string) string {
+	cmd := exec.Command(GIT, "rev-parse", "--abbrev-ref", name+"@{upstream}")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// aheadBehind returns how many commits local has that upstream lacks
+// (ahead) and vice versa (behind), computed relative to their merge-base.
+func (v Repository) aheadBehind(local, upstream string) (ahead, behind int, err error) {
+	cmd := exec.Command(GIT, "rev-list", "--left-right", "--count", local+"..."+upstream)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("fail to compute ahead/behind for %s vs %s: %s", local, upstream, err)
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output for %s vs %s: %q", local, upstream, out)
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
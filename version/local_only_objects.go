@@ -0,0 +1,56 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// LocalOnlyObjects returns object shas present in this repository's own
+// store but absent from its shared ObjectsRepository alternate, so admins
+// can decide whether to migrate or prune them. If this repository has no
+// shared alternate, every local object is returned.
+func (v Repository) LocalOnlyObjects() ([]string, error) {
+	local, err := v.allObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	shared := v.ObjectsRepository()
+	if shared == nil {
+		return local, nil
+	}
+	sharedObjects, err := shared.allObjects()
+	if err != nil {
+		return nil, err
+	}
+	sharedSet := make(map[string]struct{}, len(sharedObjects))
+	for _, sha := range sharedObjects {
+		sharedSet[sha] = struct{}{}
+	}
+
+	var localOnly []string
+	for _, sha := range local {
+		if _, ok := sharedSet[sha]; !ok {
+			localOnly = append(localOnly, sha)
+		}
+	}
+	return localOnly, nil
+}
+
+func (v Repository) allObjects() ([]string, error) {
+	cmd := exec.Command(GIT, "cat-file", "--batch-all-objects", "--batch-check=%(objectname)")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list objects in %s: %s", v.RepoDir(), err)
+	}
+
+	var shas []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			shas = append(shas, line)
+		}
+	}
+	return shas, nil
+}
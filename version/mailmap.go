@@ -0,0 +1,34 @@
+package version
+
+import (
+	"bufio"
+	"os/exec"
+	"strings"
+)
+
+// ApplyMailmap maps an author's name/email through the repository's
+// .mailmap (read via `git check-mailmap`, which honors either the
+// worktree file or the version at HEAD for bare repos), returning the
+// canonical identity. Inputs with no mapping are returned unchanged.
+func (v Repository) ApplyMailmap(name, email string) (string, string) {
+	cmd := exec.Command(GIT, "check-mailmap", name+" <"+email+">")
+	cmd.Dir = v.WorktreePath()
+	out, err := cmd.Output()
+	if err != nil {
+		return name, email
+	}
+
+	line := strings.TrimSpace(string(out))
+	scanner := bufio.NewScanner(strings.NewReader(line))
+	if !scanner.Scan() {
+		return name, email
+	}
+	mapped := scanner.Text()
+
+	lt := strings.Index(mapped, "<")
+	gt := strings.Index(mapped, ">")
+	if lt < 0 || gt < lt {
+		return name, email
+	}
+	return strings.TrimSpace(mapped[:lt]), mapped[lt+1 : gt]
+}
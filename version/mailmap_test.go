@@ -0,0 +1,44 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newMailmapTestRepo(t *testing.T) *Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	runGit(t, dir, "init")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "test")
+
+	mailmap := "Canonical Name <canonical@example.com> <old@example.com>\n"
+	if err := os.WriteFile(filepath.Join(dir, ".mailmap"), []byte(mailmap), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", ".mailmap")
+	runGit(t, dir, "commit", "-m", "add mailmap")
+
+	gitDir := filepath.Join(dir, ".git")
+	return &Repository{GitDir: gitDir, DotGit: gitDir}
+}
+
+func TestApplyMailmapMapsKnownIdentity(t *testing.T) {
+	repo := newMailmapTestRepo(t)
+
+	name, email := repo.ApplyMailmap("Old Name", "old@example.com")
+	if name != "Canonical Name" || email != "canonical@example.com" {
+		t.Errorf("ApplyMailmap = (%q, %q), want (%q, %q)", name, email, "Canonical Name", "canonical@example.com")
+	}
+}
+
+func TestApplyMailmapLeavesUnknownIdentityUnchanged(t *testing.T) {
+	repo := newMailmapTestRepo(t)
+
+	name, email := repo.ApplyMailmap("Someone Else", "someone@example.com")
+	if name != "Someone Else" || email != "someone@example.com" {
+		t.Errorf("ApplyMailmap = (%q, %q), want unchanged input", name, email)
+	}
+}
@@ -0,0 +1,37 @@
+package version
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// MatchRefs returns the fully-qualified refs matching pattern (a glob such
+// as "refs/tags/v*" or an exact ref name), using go-git's own reference
+// iteration rather than shelling out.
+func (v Repository) MatchRefs(pattern string) ([]string, error) {
+	repo := v.Raw()
+	if repo == nil {
+		return nil, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list refs in %s: %s", v.RepoDir(), err)
+	}
+	defer refs.Close()
+
+	var matches []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		if ok, _ := filepath.Match(pattern, name); ok || name == pattern {
+			matches = append(matches, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
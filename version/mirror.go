@@ -0,0 +1,54 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FetchRangeSince computes the refspecs needed to bring remoteName up to
+// date from sinceRef, so a chained mirror only transfers refs that changed
+// since that point rather than refetching everything.
+func (v Repository) FetchRangeSince(remoteName, sinceRef string) ([]string, error) {
+	current, err := v.remoteRefs(remoteName)
+	if err != nil {
+		return nil, fmt.Errorf("fail to list refs for %s: %s", remoteName, err)
+	}
+
+	known, err := v.Revlist(sinceRef, "--")
+	if err != nil {
+		return nil, fmt.Errorf("fail to resolve %s: %s", sinceRef, err)
+	}
+	knownSet := make(map[string]struct{}, len(known))
+	for _, sha := range known {
+		knownSet[sha] = struct{}{}
+	}
+
+	var refspecs []string
+	for ref, sha := range current {
+		if _, ok := knownSet[sha]; ok {
+			continue
+		}
+		refspecs = append(refspecs, fmt.Sprintf("%s:%s", ref, ref))
+	}
+	return refspecs, nil
+}
+
+func (v Repository) remoteRefs(remoteName string) (map[string]string, error) {
+	cmd := exec.Command(GIT, "ls-remote", remoteName)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+	return refs, nil
+}
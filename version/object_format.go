@@ -0,0 +1,27 @@
+package version
+
+import "fmt"
+
+// ObjectFormat returns the repository's object hash algorithm, as declared
+// by extensions.objectFormat in git config. Repositories predating the
+// extension default to "sha1".
+func (v Repository) ObjectFormat() string {
+	format := v.Config().Get("extensions.objectFormat")
+	if format == "" {
+		return "sha1"
+	}
+	return format
+}
+
+// checkObjectFormatSupported returns an error if the repository's object
+// format is not one this package knows how to operate on. SHA-256 repos
+// are recognized but fetch/checkout support depends on the underlying
+// go-git and git versions available at runtime.
+func (v Repository) checkObjectFormatSupported() error {
+	switch v.ObjectFormat() {
+	case "sha1", "sha256":
+		return nil
+	default:
+		return fmt.Errorf("unsupported object format %q", v.ObjectFormat())
+	}
+}
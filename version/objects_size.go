@@ -0,0 +1,35 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ObjectsSize returns the total byte size of the repository's object store
+// (loose objects plus packs), for feeding an LRU eviction policy over a
+// shared store cache. When this repository's objects live in a shared
+// alternate, the size is attributed to that shared ObjectsRepository
+// instead of this repository's own (empty) GitDir.
+func (v Repository) ObjectsSize() (int64, error) {
+	target := v
+	if shared := v.ObjectsRepository(); shared != nil {
+		target = *shared
+	}
+
+	objectsDir := filepath.Join(target.CommonDir(), "objects")
+
+	var size int64
+	err := filepath.Walk(objectsDir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return size, err
+}
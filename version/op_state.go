@@ -0,0 +1,47 @@
+package version
+
+import (
+	"path/filepath"
+
+	"github.com/alibaba/git-repo-go/path"
+)
+
+// OpState identifies which multi-step git operation, if any, a repository
+// is currently in the middle of.
+type OpState int
+
+const (
+	// OpNone means no rebase/merge/cherry-pick/revert/bisect is in progress.
+	OpNone OpState = iota
+	OpRebase
+	OpMerge
+	OpCherryPick
+	OpRevert
+	OpBisect
+)
+
+// IsRevertInProgress checks whether is in middle of a revert.
+func (v Repository) IsRevertInProgress() bool {
+	return path.Exist(filepath.Join(v.RepoDir(), "REVERT_HEAD"))
+}
+
+// InProgressOperation reports which operation, if any, is left half-done in
+// the repository, so callers like sync can abort early with a precise
+// message instead of proceeding into a confusing state. Checks run in the
+// order rebase, merge, cherry-pick, revert, bisect.
+func (v Repository) InProgressOperation() (OpState, error) {
+	switch {
+	case v.IsRebaseInProgress():
+		return OpRebase, nil
+	case v.IsMergeInProgress():
+		return OpMerge, nil
+	case v.IsCherryPickInProgress():
+		return OpCherryPick, nil
+	case v.IsRevertInProgress():
+		return OpRevert, nil
+	case v.IsBisectInProgress():
+		return OpBisect, nil
+	default:
+		return OpNone, nil
+	}
+}
@@ -0,0 +1,43 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// OrigHead reads CommonDir()/ORIG_HEAD, the sha git records there before a
+// reset, rebase, or merge. The bool is false when no ORIG_HEAD exists.
+func (v Repository) OrigHead() (string, bool) {
+	out, err := os.ReadFile(filepath.Join(v.CommonDir(), "ORIG_HEAD"))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
+
+// UndoToOrigHead resets the current branch back to ORIG_HEAD, undoing the
+// last reset/rebase/merge. It refuses to run against a dirty worktree.
+func (v Repository) UndoToOrigHead() error {
+	dirty, err := v.IsDirty()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("worktree is dirty, refusing to undo to ORIG_HEAD")
+	}
+
+	origHead, ok := v.OrigHead()
+	if !ok {
+		return fmt.Errorf("no ORIG_HEAD recorded in %s", v.CommonDir())
+	}
+
+	cmd := exec.Command(GIT, "reset", "--hard", origHead)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to reset to ORIG_HEAD %s: %s: %s", origHead, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// looseObjectPackThreshold is the minimum number of loose objects worth
+// paying the cost of a repack for.
+const looseObjectPackThreshold = 100
+
+// PackLooseObjects consolidates loose objects into a single pack via
+// `git repack -d`, leaving existing packs alone (repack without -a only
+// bundles what isn't packed yet). It skips the repack entirely when there
+// are fewer than looseObjectPackThreshold loose objects, since packing a
+// handful isn't worth the cost.
+func (v *Repository) PackLooseObjects() error {
+	count, err := v.looseObjectCount()
+	if err != nil {
+		return err
+	}
+	if count < looseObjectPackThreshold {
+		return nil
+	}
+
+	cmd := exec.Command(GIT, "repack", "-d")
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to repack loose objects in %s: %s: %s", v.RepoDir(), err, out)
+	}
+	return nil
+}
+
+func (v *Repository) looseObjectCount() (int, error) {
+	cmd := exec.Command(GIT, "count-objects")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("fail to count objects in %s: %s", v.RepoDir(), err)
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected `git count-objects` output: %q", out)
+	}
+	return strconv.Atoi(fields[0])
+}
@@ -0,0 +1,39 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// withFilterArgs appends --filter=<CloneFilter> to args when a partial
+// clone filter is configured, after confirming the remote advertises the
+// filter capability. It fails loudly, naming the remote, rather than
+// silently falling back to a full download.
+func (v Repository) withFilterArgs(remoteName string, args []string) ([]string, error) {
+	if v.Settings == nil || v.Settings.CloneFilter == "" {
+		return args, nil
+	}
+
+	ok, err := v.remoteAdvertisesFilter(remoteName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("remote %s does not advertise the partial clone filter capability", remoteName)
+	}
+
+	return append(args, "--filter="+v.Settings.CloneFilter), nil
+}
+
+func (v Repository) remoteAdvertisesFilter(remoteName string) (bool, error) {
+	cmd := exec.Command(GIT, "ls-remote", "--symref", remoteName)
+	cmd.Dir = v.RepoDir()
+	cmd.Env = append(os.Environ(), "GIT_TRACE_PACKET=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("fail to probe capabilities on remote %s: %s", remoteName, err)
+	}
+	return strings.Contains(string(out), "filter"), nil
+}
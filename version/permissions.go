@@ -0,0 +1,87 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NormalizePermissions compares the worktree file modes against the tree at
+// revision and repairs any mismatches: restoring stripped executable bits
+// and recreating symlinks that were checked out as plain files. It returns
+// the list of paths it repaired.
+func (v *Repository) NormalizePermissions(revision string) ([]string, error) {
+	entries, err := v.lsTreeModes(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var repaired []string
+	for relPath, mode := range entries {
+		fullPath := filepath.Join(v.Path, relPath)
+		fi, err := os.Lstat(fullPath)
+		if err != nil {
+			continue
+		}
+
+		switch mode {
+		case "120000":
+			if fi.Mode()&os.ModeSymlink != 0 {
+				continue
+			}
+			target, err := os.ReadFile(fullPath)
+			if err != nil {
+				return repaired, fmt.Errorf("fail to read %s: %s", fullPath, err)
+			}
+			os.Remove(fullPath)
+			if err := os.Symlink(strings.TrimSpace(string(target)), fullPath); err != nil {
+				return repaired, fmt.Errorf("fail to recreate symlink %s: %s", fullPath, err)
+			}
+			repaired = append(repaired, relPath)
+		case "100755":
+			if fi.Mode()&0111 != 0 {
+				continue
+			}
+			if err := os.Chmod(fullPath, fi.Mode()|0111); err != nil {
+				return repaired, fmt.Errorf("fail to set executable bit on %s: %s", fullPath, err)
+			}
+			repaired = append(repaired, relPath)
+		}
+	}
+	return repaired, nil
+}
+
+// lsTreeModes returns the mode of every blob in revision, keyed by path
+// relative to the worktree root.
+func (v Repository) lsTreeModes(revision string) (map[string]string, error) {
+	cmd := exec.Command(GIT, "ls-tree", "-r", revision)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to ls-tree %s: %s", revision, err)
+	}
+
+	modes := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		// <mode> SP <type> SP <sha>\t<path>
+		tab := strings.IndexByte(line, '\t')
+		if tab < 0 {
+			continue
+		}
+		fields := strings.Fields(line[:tab])
+		if len(fields) < 1 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		modes[line[tab+1:]] = fields[0]
+	}
+	return modes, nil
+}
@@ -0,0 +1,41 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// ErrRefProtected is returned by DeleteRef when refName matches one of
+// RepoSettings.ProtectedRefPatterns.
+var ErrRefProtected = errors.New("ref is protected and cannot be deleted")
+
+// IsProtectedRef reports whether refName matches any glob in
+// RepoSettings.ProtectedRefPatterns.
+func (v Repository) IsProtectedRef(refName string) bool {
+	if v.Settings == nil {
+		return false
+	}
+	for _, pattern := range v.Settings.ProtectedRefPatterns {
+		if ok, _ := filepath.Match(pattern, refName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteRef deletes refName, refusing when IsProtectedRef reports it as
+// protected.
+func (v Repository) DeleteRef(refName string) error {
+	if v.IsProtectedRef(refName) {
+		return fmt.Errorf("%s: %w", refName, ErrRefProtected)
+	}
+
+	cmd := exec.Command(GIT, "update-ref", "-d", refName)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to delete ref %s: %s: %s", refName, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Prune removes remote-tracking refs under refs/remotes/<remote>/ that no
+// longer exist on remote, equivalent to `git remote prune <remote>`. It
+// never touches local branches. It returns the fully-qualified ref names
+// that were pruned.
+func (v *Repository) Prune(remote string) ([]string, error) {
+	pruned, err := v.pruneGoGit(remote)
+	if err == nil {
+		return pruned, nil
+	}
+	if err != ErrUnsupported {
+		return nil, err
+	}
+	return v.pruneExec(remote)
+}
+
+func (v Repository) pruneGoGit(remote string) ([]string, error) {
+	// go-git does not expose an equivalent of `git remote prune`; defer to
+	// the exec backend.
+	return nil, ErrUnsupported
+}
+
+func (v Repository) pruneExec(remote string) ([]string, error) {
+	cmd := exec.Command(GIT, "remote", "prune", remote)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("fail to prune remote %s: %s: %s", remote, err, out)
+	}
+
+	var pruned []string
+	s := bufio.NewScanner(strings.NewReader(string(out)))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(line, "* [pruned]") {
+			continue
+		}
+		ref := strings.TrimSpace(strings.TrimPrefix(line, "* [pruned]"))
+		pruned = append(pruned, "refs/remotes/"+ref)
+	}
+	return pruned, nil
+}
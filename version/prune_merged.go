@@ -0,0 +1,41 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// MergedRemoteBranches returns the remote-tracking branches whose tips are
+// ancestors of baseRef, i.e. branches that have already been fully merged
+// and are safe for a mirror to drop.
+func (v Repository) MergedRemoteBranches(baseRef string) ([]string, error) {
+	cmd := exec.Command(GIT, "branch", "-r", "--merged", baseRef)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list merged remote branches: %s", err)
+	}
+
+	var merged []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		ref := strings.TrimSpace(line)
+		if ref == "" || strings.Contains(ref, "->") {
+			continue
+		}
+		merged = append(merged, ref)
+	}
+	return merged, nil
+}
+
+// DeleteRemoteTrackingRefs deletes each of refs from the local ref store.
+func (v Repository) DeleteRemoteTrackingRefs(refs []string) error {
+	for _, ref := range refs {
+		cmd := exec.Command(GIT, "update-ref", "-d", "refs/remotes/"+strings.TrimPrefix(ref, "refs/remotes/"))
+		cmd.Dir = v.RepoDir()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("fail to delete %s: %s: %s", ref, err, out)
+		}
+	}
+	return nil
+}
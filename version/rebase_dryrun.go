@@ -0,0 +1,56 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RebaseWouldConflict performs a dry-run rebase of HEAD onto upstreamRef in
+// a scratch worktree, reporting whether it would conflict and which files
+// are involved, without touching the real worktree.
+func (v Repository) RebaseWouldConflict(upstreamRef string) (bool, []string, error) {
+	scratch, err := os.MkdirTemp("", "rebase-dryrun-")
+	if err != nil {
+		return false, nil, err
+	}
+	defer os.RemoveAll(scratch)
+
+	add := exec.Command(GIT, "worktree", "add", "--detach", scratch, "HEAD")
+	add.Dir = v.RepoDir()
+	if out, err := add.CombinedOutput(); err != nil {
+		return false, nil, fmt.Errorf("fail to create scratch worktree: %s: %s", err, out)
+	}
+	defer func() {
+		remove := exec.Command(GIT, "worktree", "remove", "--force", scratch)
+		remove.Dir = v.RepoDir()
+		remove.Run()
+	}()
+
+	rebase := exec.Command(GIT, "rebase", upstreamRef)
+	rebase.Dir = scratch
+	out, err := rebase.CombinedOutput()
+	if err == nil {
+		return false, nil, nil
+	}
+
+	status := exec.Command(GIT, "diff", "--name-only", "--diff-filter=U")
+	status.Dir = scratch
+	unmerged, _ := status.Output()
+
+	abort := exec.Command(GIT, "rebase", "--abort")
+	abort.Dir = scratch
+	abort.Run()
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(string(unmerged), "\n"), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	if len(files) == 0 {
+		return false, nil, fmt.Errorf("rebase dry-run failed for a reason other than conflicts: %s: %s", err, out)
+	}
+	return true, files, nil
+}
@@ -0,0 +1,44 @@
+package version
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoReflogEntry is returned by RecoverBranch when no reflog entry
+// mentions the deleted branch's tip.
+var ErrNoReflogEntry = errors.New("no reflog entry found for branch")
+
+// RecoverBranch scans the HEAD reflog for the last known tip of a deleted
+// branch named name and recreates refs/heads/<name> pointing at it.
+func (v *Repository) RecoverBranch(name string) (string, error) {
+	cmd := exec.Command(GIT, "reflog", "show", "--all", "--format=%H %gs")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	needle := "to " + name
+	var sha string
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.Contains(line, needle) {
+			fields := strings.Fields(line)
+			if len(fields) > 0 {
+				sha = fields[0]
+				break
+			}
+		}
+	}
+	if sha == "" {
+		return "", ErrNoReflogEntry
+	}
+
+	cmd = exec.Command(GIT, "update-ref", "refs/heads/"+name, sha)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", errors.New(string(out))
+	}
+	return sha, nil
+}
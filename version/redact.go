@@ -0,0 +1,15 @@
+package version
+
+import "net/url"
+
+// RedactURL strips any embedded userinfo (user:token@) from u before it is
+// safe to log, so credentials in a RemoteURL never end up in fetch/push/
+// setRemote log lines. URLs that don't parse are returned unchanged.
+func RedactURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.User == nil {
+		return u
+	}
+	parsed.User = nil
+	return parsed.String()
+}
@@ -0,0 +1,31 @@
+package version
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// ResolveRefAmbiguity returns every fully-qualified ref that a short name
+// could refer to (refs/heads/<name>, refs/tags/<name>, and any matching
+// refs/remotes/*/<name>), so a caller can detect and warn about ambiguity
+// instead of silently picking git's default resolution order.
+func (v Repository) ResolveRefAmbiguity(name string) ([]string, error) {
+	cmd := exec.Command(GIT, "for-each-ref", "--format=%(refname)",
+		"refs/heads/"+name,
+		"refs/tags/"+name,
+		"refs/remotes/*/"+name,
+	)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			candidates = append(candidates, line)
+		}
+	}
+	return candidates, nil
+}
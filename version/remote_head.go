@@ -0,0 +1,71 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FollowRemoteHeadRename re-reads remoteName's symbolic HEAD and, if it now
+// points at a different branch than the locally cached
+// refs/remotes/<remote>/HEAD, updates the local symref to match. This keeps
+// mirrors following a server-side default branch rename (e.g. master to
+// main). It reports whether anything changed.
+func (v *Repository) FollowRemoteHeadRename(remoteName string) (bool, error) {
+	remoteBranch, err := v.remoteSymrefHead(remoteName)
+	if err != nil {
+		return false, err
+	}
+
+	localHeadRef := "refs/remotes/" + remoteName + "/HEAD"
+	current, err := v.symbolicRef(localHeadRef)
+	if err != nil {
+		// No cached HEAD yet; set it without reporting a rename.
+		return false, v.setSymbolicRef(localHeadRef, "refs/remotes/"+remoteName+"/"+remoteBranch)
+	}
+
+	newTarget := "refs/remotes/" + remoteName + "/" + remoteBranch
+	if current == newTarget {
+		return false, nil
+	}
+
+	if err := v.setSymbolicRef(localHeadRef, newTarget); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (v Repository) remoteSymrefHead(remoteName string) (string, error) {
+	cmd := exec.Command(GIT, "ls-remote", "--symref", remoteName, "HEAD")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("fail to read remote HEAD for %s: %s", remoteName, err)
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "ref: refs/heads/") {
+			fields := strings.Fields(line)
+			return strings.TrimPrefix(fields[1], "refs/heads/"), nil
+		}
+	}
+	return "", fmt.Errorf("remote %s did not report a symbolic HEAD", remoteName)
+}
+
+func (v Repository) symbolicRef(ref string) (string, error) {
+	cmd := exec.Command(GIT, "symbolic-ref", ref)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (v Repository) setSymbolicRef(ref, target string) error {
+	cmd := exec.Command(GIT, "symbolic-ref", ref, target)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to set %s: %s: %s", ref, err, out)
+	}
+	return nil
+}
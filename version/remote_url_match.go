@@ -0,0 +1,31 @@
+package version
+
+import "strings"
+
+// NormalizeRemoteURL makes two URLs that point at the same remote but are
+// spelled differently (trailing slash, ".git" suffix, mismatched case in an
+// scp-like host) comparable.
+func NormalizeRemoteURL(url string) string {
+	url = strings.TrimSpace(url)
+	url = strings.TrimSuffix(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+	if !strings.Contains(url, "://") {
+		if colon := strings.Index(url, ":"); colon > 0 {
+			at := strings.LastIndex(url[:colon], "@")
+			url = url[:at+1] + strings.ToLower(url[at+1:colon]) + url[colon:]
+		}
+	}
+	return url
+}
+
+// RemoteURLMatchesManifest reports whether the configured URL for
+// remoteName still matches the manifest-declared v.RemoteURL (after
+// NormalizeRemoteURL). When it does not, the configured value is returned
+// so the caller can decide whether to rewrite it.
+func (v Repository) RemoteURLMatchesManifest(remoteName string) (bool, string, error) {
+	configured := v.GitConfigRemoteURL(remoteName)
+	if NormalizeRemoteURL(configured) == NormalizeRemoteURL(v.RemoteURL) {
+		return true, configured, nil
+	}
+	return false, configured, nil
+}
@@ -0,0 +1,198 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var remoteConfigKeyPattern = regexp.MustCompile(`^remote\.(.+)\.(url|pushurl|fetch)$`)
+
+// RemoteConfig surfaces the git config state of a single remote. Remote is
+// an alias kept for callers that look up a single entry by name.
+type RemoteConfig struct {
+	Name         string
+	URL          string
+	PushURL      string
+	FetchRefspec string
+}
+
+// Remote is an alias for RemoteConfig, used by RemoteMap's lookup API.
+type Remote = RemoteConfig
+
+// RemoteMap indexes a repository's known remotes by name. Its zero value is
+// an empty map ready to use; its methods take care of the locking needed to
+// read and write it from concurrent sync goroutines.
+//
+// DefaultTrackingBranch and setRemote both only ever deal with a single,
+// implicit "primary" remote (the one setRemote was originally called
+// with) rather than consulting this map, so a project with several
+// remotes still has exactly one that drives tracking-branch resolution.
+type RemoteMap struct {
+	mu      sync.RWMutex
+	remotes map[string]*RemoteConfig
+}
+
+// Get looks up a remote by name.
+func (m *RemoteMap) Get(name string) (*Remote, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.remotes[name]
+	return r, ok
+}
+
+// Names returns the configured remote names, sorted.
+func (m *RemoteMap) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.remotes))
+	for name := range m.remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Each calls fn for every remote in the map, in Names order, stopping and
+// returning the first error fn reports. fn is called without the map lock
+// held, so it may itself call back into Get/Names/Each.
+func (m *RemoteMap) Each(fn func(*Remote) error) error {
+	m.mu.RLock()
+	remotes := make([]*Remote, 0, len(m.remotes))
+	for _, name := range m.sortedNamesLocked() {
+		remotes = append(remotes, m.remotes[name])
+	}
+	m.mu.RUnlock()
+
+	for _, remote := range remotes {
+		if err := fn(remote); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *RemoteMap) sortedNamesLocked() []string {
+	names := make([]string, 0, len(m.remotes))
+	for name := range m.remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *RemoteMap) set(name string, remote *RemoteConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.remotes == nil {
+		m.remotes = make(map[string]*RemoteConfig)
+	}
+	m.remotes[name] = remote
+}
+
+func (m *RemoteMap) delete(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.remotes, name)
+}
+
+// ForEach is retained for callers written against the earlier, unordered
+// iteration API; Each is preferred for new code.
+func (m *RemoteMap) ForEach(fn func(*RemoteConfig) error) error {
+	return m.Each(fn)
+}
+
+// RemoteAdd adds a new remote to the repository's git config, fetching all
+// of its branches by default. It returns an error if a remote by that name
+// already exists, unless overwrite is true.
+func (v *Repository) RemoteAdd(name, url string, overwrite bool) error {
+	cfg := v.Config()
+	if !overwrite && cfg.Get("remote."+name+".url") != "" {
+		return fmt.Errorf("remote %s already exists", name)
+	}
+
+	cfg.Set("remote."+name+".url", url)
+	cfg.Set("remote."+name+".fetch", "+refs/heads/*:refs/remotes/"+name+"/*")
+	if err := v.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("fail to add remote %s: %s", name, err)
+	}
+
+	if v.Remotes == nil {
+		v.Remotes = &RemoteMap{}
+	}
+	v.Remotes.set(name, &RemoteConfig{
+		Name:         name,
+		URL:          url,
+		FetchRefspec: "+refs/heads/*:refs/remotes/" + name + "/*",
+	})
+	return nil
+}
+
+// RemoteRemove drops a remote from the repository's git config and from
+// v.Remotes. It does not remove the remote-tracking refs already fetched
+// under refs/remotes/<name>/.
+func (v *Repository) RemoteRemove(name string) error {
+	cfg := v.Config()
+	cfg.Unset("remote." + name + ".url")
+	cfg.Unset("remote." + name + ".pushurl")
+	cfg.Unset("remote." + name + ".fetch")
+	if err := v.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("fail to remove remote %s: %s", name, err)
+	}
+
+	if v.Remotes != nil {
+		v.Remotes.delete(name)
+	}
+	return nil
+}
+
+// RemoteList returns the remotes configured for this repository.
+func (v Repository) RemoteList() ([]RemoteConfig, error) {
+	cmd := exec.Command(GIT, "config", "--get-regexp", `^remote\..*\.(url|pushurl|fetch)$`)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			// No remotes configured.
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fail to list remotes in %s: %s", v.RepoDir(), err)
+	}
+
+	byName := make(map[string]*RemoteConfig)
+	order := []string{}
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		m := remoteConfigKeyPattern.FindStringSubmatch(fields[0])
+		if m == nil {
+			continue
+		}
+		name, key, value := m[1], m[2], fields[1]
+		rc, ok := byName[name]
+		if !ok {
+			rc = &RemoteConfig{Name: name}
+			byName[name] = rc
+			order = append(order, name)
+		}
+		switch key {
+		case "url":
+			rc.URL = value
+		case "pushurl":
+			rc.PushURL = value
+		case "fetch":
+			rc.FetchRefspec = value
+		}
+	}
+
+	remotes := make([]RemoteConfig, 0, len(order))
+	for _, name := range order {
+		remotes = append(remotes, *byName[name])
+	}
+	return remotes, nil
+}
@@ -0,0 +1,51 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RepairGitdirPointer rewrites the worktree's .git file and the shared
+// repository's worktrees/<name>/gitdir back-pointer to point at newGitDir,
+// fixing both directions after a .repo tree is relocated. It validates that
+// both pointers resolve before returning.
+func (v *Repository) RepairGitdirPointer(newGitDir string) error {
+	newGitDir, err := filepath.Abs(newGitDir)
+	if err != nil {
+		return err
+	}
+
+	dotGit := filepath.Join(v.Path, ".git")
+	if err := os.WriteFile(dotGit, []byte("gitdir: "+newGitDir+"\n"), 0644); err != nil {
+		return fmt.Errorf("fail to rewrite %s: %s", dotGit, err)
+	}
+
+	worktreeName := filepath.Base(v.Path)
+	backPointer := filepath.Join(newGitDir, "worktrees", worktreeName, "gitdir")
+	if _, err := os.Stat(filepath.Dir(backPointer)); err == nil {
+		absDotGit, err := filepath.Abs(dotGit)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(backPointer, []byte(absDotGit+"\n"), 0644); err != nil {
+			return fmt.Errorf("fail to rewrite %s: %s", backPointer, err)
+		}
+	}
+
+	v.GitDir = newGitDir
+
+	if !dirLooksLikeGitDir(newGitDir) {
+		return fmt.Errorf("repaired gitdir %s does not resolve to a valid git directory", newGitDir)
+	}
+	return nil
+}
+
+func dirLooksLikeGitDir(dir string) bool {
+	for _, marker := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// RepairIndex resets the index to revision and refreshes its stat cache,
+// reconciling an index left inconsistent by a crashed checkout without
+// discarding whatever content already landed in the worktree.
+func (v *Repository) RepairIndex(revision string) error {
+	readTree := exec.Command(GIT, "read-tree", revision)
+	readTree.Dir = v.RepoDir()
+	if out, err := readTree.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to reset index to %s: %s: %s", revision, err, out)
+	}
+
+	refresh := exec.Command(GIT, "update-index", "--refresh")
+	refresh.Dir = v.RepoDir()
+	// update-index --refresh exits non-zero when it finds worktree files
+	// that differ from the index, which is expected after a crash and not
+	// itself a failure of the repair.
+	refresh.Run()
+	return nil
+}
@@ -1,11 +1,16 @@
-package project
+package version
 
 import (
 	"bufio"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alibaba/git-repo-go/common"
 	"github.com/alibaba/git-repo-go/config"
@@ -40,6 +45,10 @@ type Repository struct {
 	Reference string // Alternate repository
 	Settings  *RepoSettings
 	raw       *git.Repository
+
+	cfgCache     goconfig.GitConfig
+	cfgCacheFile string
+	cfgCacheTime int64
 }
 
 // RepoDir returns git dir of the repository
@@ -101,45 +110,89 @@ func (v *Repository) setRemote(remoteName, remoteURL string) error {
 	if remoteURL != "" {
 		v.RemoteURL = remoteURL
 	}
+	mirror := v.Settings != nil && v.Settings.Mirror
+
 	cfg := v.Config()
 	changed := false
-	if !v.IsBare {
+	if mirror {
+		cfg.Set("core.bare", "true")
+		changed = true
+	} else if !v.IsBare {
 		cfg.Unset("core.bare")
 		cfg.Set("core.logAllRefUpdates", "true")
 		changed = true
 	}
 	if remoteName != "" && remoteURL != "" {
 		cfg.Set("remote."+remoteName+".url", v.RemoteURL)
-		cfg.Set("remote."+remoteName+".fetch", "+refs/heads/*:refs/remotes/"+remoteName+"/*")
+		if mirror {
+			cfg.Set("remote."+remoteName+".fetch", "+refs/*:refs/*")
+			cfg.Set("remote."+remoteName+".mirror", "true")
+		} else {
+			cfg.Set("remote."+remoteName+".fetch", "+refs/heads/*:refs/remotes/"+remoteName+"/*")
+			cfg.Unset("remote." + remoteName + ".mirror")
+		}
+		if v.Settings != nil && v.Settings.CloneFilter != "" {
+			cfg.Set("remote."+remoteName+".partialclonefilter", v.Settings.CloneFilter)
+		}
+		log.Debugf("set remote %s to %s", remoteName, RedactURL(v.RemoteURL))
 		changed = true
 	}
 	if changed {
-		err = cfg.Save(v.configFile())
+		err = v.SaveConfig(cfg)
 	}
 	return err
 }
 
-func (v Repository) setAlternates(reference string) {
-	var err error
+func (v Repository) setAlternates(references ...string) {
+	for _, reference := range references {
+		if reference == "" {
+			continue
+		}
+		if err := v.AddAlternate(reference); err != nil {
+			log.Errorf("fail to set info/alternates on %s: %s", v.GitDir, err)
+		}
+	}
+}
 
-	if reference != "" {
-		// create file: objects/info/alternates
-		altFile := filepath.Join(v.GitDir, "objects", "info", "alternates")
-		os.MkdirAll(filepath.Dir(altFile), 0755)
-		var f *os.File
-		f, err = file.New(altFile).OpenCreateRewrite()
-		defer f.Close()
-		if err == nil {
-			relPath := filepath.Join(reference, "objects")
-			relPath, err = filepath.Rel(filepath.Join(v.GitDir, "objects"), relPath)
-			if err == nil {
-				_, err = f.WriteString(relPath + "\n")
-			}
-			if err != nil {
-				log.Errorf("fail to set info/alternates on %s: %s", v.GitDir, err)
+// AddAlternate appends reference's objects directory to
+// objects/info/alternates, deduplicated against whatever is already listed
+// there. Calling it twice with the same reference is a no-op the second
+// time.
+func (v Repository) AddAlternate(reference string) error {
+	objectsDir := filepath.Join(v.GitDir, "objects")
+	relPath, err := filepath.Rel(objectsDir, filepath.Join(reference, "objects"))
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	altFile := v.alternatesFile()
+	if f, err := os.Open(altFile); err == nil {
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			if line := strings.TrimSpace(s.Text()); line != "" {
+				existing[line] = true
 			}
 		}
+		f.Close()
+	}
+	if existing[relPath] {
+		return nil
+	}
+
+	os.MkdirAll(filepath.Dir(altFile), 0755)
+	f, err := file.New(altFile).OpenCreateRewrite()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for line := range existing {
+		if _, err := f.WriteString(line + "\n"); err != nil {
+			return err
+		}
 	}
+	_, err = f.WriteString(relPath + "\n")
+	return err
 }
 
 // GitConfigRemoteURL returns remote url in git config.
@@ -169,8 +222,82 @@ func (v Repository) HasAlternates() bool {
 	return true
 }
 
-func (v Repository) applyCloneBundle() {
-	// TODO: download and clone from bundle file
+// applyCloneBundle serves the initial clone from a CDN-hosted clone.bundle
+// alongside v.RemoteURL when available, so large mirrors don't have to pull
+// every object over the network on first sync. It downloads the bundle into
+// GitDir, verifies it, and fetches from the local bundle file; the caller
+// still needs to fetch the real remote afterwards to pick up anything newer
+// than the bundle. Missing or empty bundles are skipped without error.
+func (v Repository) applyCloneBundle() error {
+	if v.Settings != nil && v.Settings.DisableCloneBundle {
+		return nil
+	}
+
+	bundleURL := strings.TrimSuffix(v.RemoteURL, "/") + "/clone.bundle"
+	bundlePath := filepath.Join(v.GitDir, "clone.bundle")
+
+	ok, err := downloadCloneBundle(bundleURL, bundlePath)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	verify := exec.Command(GIT, "bundle", "verify", bundlePath)
+	verify.Dir = v.RepoDir()
+	if out, err := verify.CombinedOutput(); err != nil {
+		os.Remove(bundlePath)
+		log.Warnf("clone bundle %s failed verification, ignoring: %s: %s", bundleURL, err, out)
+		return nil
+	}
+
+	fetch := exec.Command(GIT, "fetch", bundlePath, "*:*")
+	fetch.Dir = v.RepoDir()
+	if out, err := fetch.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to fetch from clone bundle %s: %s: %s", bundlePath, err, out)
+	}
+	return nil
+}
+
+// downloadCloneBundle downloads url into dest, returning false (with no
+// error) when the server has no bundle to offer. A partially written file
+// left behind by an interrupted transfer is cleaned up.
+func downloadCloneBundle(url, dest string) (ok bool, err error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return false, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("fail to download clone bundle %s: %s", url, resp.Status)
+	}
+
+	os.MkdirAll(filepath.Dir(dest), 0755)
+	f, err := os.Create(dest)
+	if err != nil {
+		return false, err
+	}
+
+	n, copyErr := io.Copy(f, resp.Body)
+	closeErr := f.Close()
+
+	if copyErr != nil || closeErr != nil {
+		os.Remove(dest)
+		if copyErr != nil {
+			return false, fmt.Errorf("interrupted downloading clone bundle %s: %s", url, copyErr)
+		}
+		return false, closeErr
+	}
+	if n == 0 {
+		os.Remove(dest)
+		return false, nil
+	}
+	return true, nil
 }
 
 // GetHead returns current branch name
@@ -197,6 +324,32 @@ func (v Repository) GetHead() string {
 	return head
 }
 
+// GetHeadRef returns the raw contents of HEAD, plus whether it is detached
+// (pointing directly at a SHA rather than a "ref: refs/heads/..." symref).
+// Unlike GetHead, which returns "" for a detached HEAD, this lets callers
+// tell "on branch X" apart from "detached at X".
+func (v Repository) GetHeadRef() (ref string, detached bool, err error) {
+	headFile := filepath.Join(v.RepoDir(), "HEAD")
+	if !path.IsFile(headFile) {
+		return "", false, fmt.Errorf("fail to find HEAD file in %s", v.RepoDir())
+	}
+	f, err := os.Open(headFile)
+	if err != nil {
+		return "", false, fmt.Errorf("fail to open HEAD file: %s", err)
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	if !s.Scan() {
+		return "", false, fmt.Errorf("fail to read HEAD file: %s", s.Err())
+	}
+	line := s.Text()
+	if strings.HasPrefix(line, "ref: ") {
+		return line[5:], false, nil
+	}
+	return line, true, nil
+}
+
 // IsRebaseInProgress checks whether is in middle of a rebase.
 func (v Repository) IsRebaseInProgress() bool {
 	gitDir := v.RepoDir()
@@ -218,28 +371,49 @@ func (v Repository) RevisionIsValid(revision string) bool {
 	return false
 }
 
-// LastModified gets last modified time of a revision
+// LastModified gets last modified time of a revision, formatted the way
+// `git log` prints a date. Prefer LastModifiedTime for anything that needs
+// to do date math or localization instead of reparsing this string.
 func (v Repository) LastModified(revision string) string {
-	raw := v.Raw()
+	when, err := v.LastModifiedTime(revision)
+	if err != nil {
+		return ""
+	}
+	return when.Format("Mon Jan 2 15:04:05 -0700 2006")
+}
 
+// LastModifiedTime returns the committer timestamp of revision. It returns
+// a zero time.Time and a non-nil error for an empty repository or a
+// revision that doesn't resolve, rather than a value callers could mistake
+// for the epoch.
+func (v Repository) LastModifiedTime(revision string) (time.Time, error) {
+	raw := v.Raw()
 	if raw == nil {
-		return ""
+		return time.Time{}, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
 	}
 	obj, err := raw.ResolveRevision(plumbing.Revision(revision))
 	if err != nil {
-		return ""
+		return time.Time{}, fmt.Errorf("fail to resolve %s: %s", revision, err)
 	}
 	commit, err := raw.CommitObject(*obj)
 	if err != nil {
-		return ""
+		return time.Time{}, fmt.Errorf("fail to load commit %s: %s", revision, err)
 	}
-
-	return commit.Committer.When.Format("Mon Jan 2 15:04:05 -0700 2006")
+	return commit.Committer.When, nil
 }
 
-// Revlist works like rev-list.
-// TODO: Hack go-git plumbing/revlist package to replace git exec
+// Revlist works like rev-list, computing results natively from v.Raw()
+// where the given flags are understood (--count, --max-count=N, "A..B"
+// ranges, plain revisions), and falling back to the git exec path for
+// anything else (e.g. --left-right, --not) so behavior never regresses.
 func (v Repository) Revlist(args ...string) ([]string, error) {
+	if shas, isCount, ok := v.revlistNative(args); ok {
+		if isCount {
+			return []string{strconv.Itoa(len(shas))}, nil
+		}
+		return shas, nil
+	}
+
 	result := []string{}
 	cmdArgs := []string{
 		"git",
@@ -278,22 +452,55 @@ func (v Repository) Revlist(args ...string) ([]string, error) {
 	return result, nil
 }
 
-// Raw returns go-git repository object.
-func (v Repository) Raw() *git.Repository {
-	var (
-		err error
-	)
-
-	if v.raw != nil {
-		return v.raw
+// RevlistCount is Revlist with --count, returning the count as an int
+// directly instead of making every caller parse the single-line result.
+func (v Repository) RevlistCount(args ...string) (int, error) {
+	result, err := v.Revlist(append([]string{"--count"}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
 	}
+	return strconv.Atoi(result[0])
+}
 
-	v.raw, err = git.PlainOpen(v.CommonDir())
+// Raw opens (and caches) the go-git handle for this repository, logging
+// and returning nil on failure. It is kept for backward compatibility;
+// callers that want the underlying error should use OpenRaw instead.
+//
+// Raw and OpenRaw both take a pointer receiver, so the v.raw cache persists
+// across calls on the same *Repository. Methods with a value receiver (such
+// as Revlist, RevisionIsValid and LastModifiedTime) call Raw on their own
+// copy, so a handle they open is not kept once they return unless the
+// original Repository had already cached one.
+func (v *Repository) Raw() *git.Repository {
+	repo, err := v.OpenRaw()
 	if err != nil {
 		log.Errorf("cannot open git repo '%s': %s", v.RepoDir(), err)
 		return nil
 	}
-	return v.raw
+	return repo
+}
+
+// OpenRaw opens (and caches) the go-git handle for this repository,
+// returning the real error instead of logging it, so library callers can
+// decide how to react instead of getting a bare nil.
+func (v *Repository) OpenRaw() (*git.Repository, error) {
+	if v.raw != nil {
+		return v.raw, nil
+	}
+
+	if v.ObjectFormat() == "sha256" {
+		return nil, fmt.Errorf("go-git does not support the sha256 object format")
+	}
+
+	raw, err := git.PlainOpen(v.CommonDir())
+	if err != nil {
+		return nil, err
+	}
+	v.raw = raw
+	return v.raw, nil
 }
 
 func (v Repository) configFile() string {
@@ -305,24 +512,53 @@ func (v Repository) SSHInfoCacheFile() string {
 	return filepath.Join(v.RepoDir(), "info", "sshinfo.cache")
 }
 
-// Config returns git config file parser.
-func (v Repository) Config() goconfig.GitConfig {
-	cfg, err := goconfig.Load(v.configFile())
+// Config returns git config file parser. The parsed config is cached on the
+// Repository, keyed on configFile() (so alternates/objects repositories
+// sharing a commondir share the same cache entry), and is reloaded whenever
+// the config file's mtime moves past what was cached.
+func (v *Repository) Config() goconfig.GitConfig {
+	configFile := v.configFile()
+	if v.cfgCache != nil && v.cfgCacheFile == configFile {
+		if fi, err := os.Stat(configFile); err == nil && fi.ModTime().UnixNano() == v.cfgCacheTime {
+			return v.cfgCache
+		}
+	}
+
+	cfg, err := goconfig.Load(configFile)
 	if err != nil && err != goconfig.ErrNotExist {
-		log.Fatalf("fail to load config: %s: %s", v.configFile(), err)
+		log.Fatalf("fail to load config: %s: %s", configFile, err)
 	}
 	if cfg == nil {
 		cfg = goconfig.NewGitConfig()
 	}
+
+	v.cfgCache = cfg
+	v.cfgCacheFile = configFile
+	if fi, err := os.Stat(configFile); err == nil {
+		v.cfgCacheTime = fi.ModTime().UnixNano()
+	}
 	return cfg
 }
 
+// ReloadConfig discards the cached config, forcing the next Config() call to
+// re-read it from disk. Callers that mutate the config file out of band
+// (e.g. shelling out to `git config`) should call this afterwards.
+func (v *Repository) ReloadConfig() {
+	v.cfgCache = nil
+	v.cfgCacheFile = ""
+	v.cfgCacheTime = 0
+}
+
 // SaveConfig will save config to git config file.
 func (v *Repository) SaveConfig(cfg goconfig.GitConfig) error {
 	if cfg == nil {
 		cfg = goconfig.NewGitConfig()
 	}
-	return cfg.Save(v.configFile())
+	if err := cfg.Save(v.configFile()); err != nil {
+		return err
+	}
+	v.ReloadConfig()
+	return nil
 }
 
 // Prompt will show project path as prompt.
@@ -0,0 +1,23 @@
+package version
+
+import "net/url"
+
+// ReviewURL returns the review server URL for the project's remote, as
+// declared by the manifest's <remote review="..."> attribute on the
+// project's ManifestRemote, falling back to the fetch host when the
+// manifest doesn't declare one.
+func (v Repository) ReviewURL() (string, error) {
+	if v.ManifestRemote != nil && v.ManifestRemote.Review != "" {
+		return v.ManifestRemote.Review, nil
+	}
+
+	remoteURL := v.RemoteURL
+	if remoteURL == "" {
+		remoteURL = v.GitConfigRemoteURL("origin")
+	}
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", err
+	}
+	return parsed.Scheme + "://" + parsed.Host, nil
+}
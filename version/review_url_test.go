@@ -0,0 +1,36 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/alibaba/git-repo-go/manifest"
+)
+
+func TestReviewURLDeclaredOnRemote(t *testing.T) {
+	repo := Repository{
+		Project:   manifest.Project{ManifestRemote: &manifest.Remote{Review: "https://review.example.com"}},
+		RemoteURL: "https://git.example.com/some/repo",
+	}
+
+	got, err := repo.ReviewURL()
+	if err != nil {
+		t.Fatalf("ReviewURL: %s", err)
+	}
+	if got != "https://review.example.com" {
+		t.Errorf("ReviewURL = %q, want %q", got, "https://review.example.com")
+	}
+}
+
+func TestReviewURLFallsBackToFetchHost(t *testing.T) {
+	repo := Repository{
+		RemoteURL: "https://git.example.com/some/repo",
+	}
+
+	got, err := repo.ReviewURL()
+	if err != nil {
+		t.Fatalf("ReviewURL: %s", err)
+	}
+	if got != "https://git.example.com" {
+		t.Errorf("ReviewURL = %q, want %q", got, "https://git.example.com")
+	}
+}
@@ -0,0 +1,27 @@
+package version
+
+// RevisionOverride records a project whose pinned Revision diverges from
+// the manifest's default revision.
+type RevisionOverride struct {
+	Project         string
+	Revision        string
+	DefaultRevision string
+}
+
+// FindRevisionOverrides returns, for manifest linting, every project whose
+// Revision differs from ManifestDefaultRevision, so maintainers can review
+// which pins are intentional.
+func FindRevisionOverrides(repos []*Repository) []RevisionOverride {
+	var overrides []RevisionOverride
+	for _, r := range repos {
+		if r.Revision == "" || r.Revision == r.ManifestDefaultRevision {
+			continue
+		}
+		overrides = append(overrides, RevisionOverride{
+			Project:         r.Name,
+			Revision:        r.Revision,
+			DefaultRevision: r.ManifestDefaultRevision,
+		})
+	}
+	return overrides
+}
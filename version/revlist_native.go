@@ -0,0 +1,112 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+// revlistNative attempts to satisfy args using v.Raw() by walking commits,
+// supporting the flag set Revlist callers actually use: --count,
+// --max-count=N, "A..B" ranges, and plain revisions. It returns ok=false
+// when args contain something it doesn't understand (e.g. --left-right),
+// so the caller can fall back to the exec path without any behavior
+// regression.
+func (v Repository) revlistNative(args []string) (result []string, count bool, ok bool) {
+	raw := v.Raw()
+	if raw == nil {
+		return nil, false, false
+	}
+
+	maxCount := -1
+	var included, excluded []string
+
+	for _, arg := range args {
+		switch {
+		case arg == "--count":
+			count = true
+		case strings.HasPrefix(arg, "--max-count="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--max-count="))
+			if err != nil {
+				return nil, false, false
+			}
+			maxCount = n
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && arg[1] >= '0' && arg[1] <= '9':
+			n, err := strconv.Atoi(arg[1:])
+			if err != nil {
+				return nil, false, false
+			}
+			maxCount = n
+		case strings.HasPrefix(arg, "--"):
+			// Anything else (e.g. --left-right, --not) we don't model
+			// natively.
+			return nil, false, false
+		case strings.Contains(arg, ".."):
+			parts := strings.SplitN(arg, "..", 2)
+			excluded = append(excluded, parts[0])
+			included = append(included, parts[1])
+		default:
+			included = append(included, arg)
+		}
+	}
+
+	excludedSet, err := reachableSHAs(raw, excluded)
+	if err != nil {
+		return nil, false, false
+	}
+
+	var shas []string
+	for _, rev := range included {
+		hash, err := raw.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return nil, false, false
+		}
+		iter, err := raw.Log(&git.LogOptions{From: *hash})
+		if err != nil {
+			return nil, false, false
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			if _, skip := excludedSet[c.Hash.String()]; skip {
+				return nil
+			}
+			if maxCount >= 0 && len(shas) >= maxCount {
+				return storer.ErrStop
+			}
+			shas = append(shas, c.Hash.String())
+			return nil
+		})
+		iter.Close()
+		if err != nil && err != storer.ErrStop {
+			return nil, false, false
+		}
+	}
+
+	return shas, count, true
+}
+
+func reachableSHAs(raw *git.Repository, revs []string) (map[string]struct{}, error) {
+	set := make(map[string]struct{})
+	for _, rev := range revs {
+		hash, err := raw.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return nil, err
+		}
+		iter, err := raw.Log(&git.LogOptions{From: *hash})
+		if err != nil {
+			return nil, err
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			set[c.Hash.String()] = struct{}{}
+			return nil
+		})
+		iter.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
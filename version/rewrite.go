@@ -0,0 +1,41 @@
+package version
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// UpstreamWasRewritten returns true if upstreamRef's previous tip, as
+// recorded in localRef's reflog, is no longer an ancestor of upstreamRef's
+// current tip. This indicates the upstream branch was rebased or
+// force-pushed since the last fetch, and a plain fast-forward will not
+// realign the local tracking branch.
+func (v Repository) UpstreamWasRewritten(localRef, upstreamRef string) (bool, error) {
+	oldTip, err := v.reflogPreviousTip(localRef)
+	if err != nil || oldTip == "" {
+		// No prior reflog entry means nothing to compare against.
+		return false, nil
+	}
+
+	cmd := exec.Command(GIT, "merge-base", "--is-ancestor", oldTip, upstreamRef)
+	cmd.Dir = v.RepoDir()
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// reflogPreviousTip returns the SHA localRef pointed to before its most
+// recent reflog update, or "" if there is no such entry.
+func (v Repository) reflogPreviousTip(localRef string) (string, error) {
+	cmd := exec.Command(GIT, "rev-parse", localRef+"@{1}")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,46 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SafeRemoveWorktree removes the project's worktree and gitdir, for when a
+// manifest update drops the project entirely. It refuses to delete a
+// worktree with uncommitted changes (IsDirty) or unpushed commits ahead of
+// its tracking branch, unless force is true.
+func (v *Repository) SafeRemoveWorktree(force bool) error {
+	if !force {
+		if !v.IsBare {
+			dirty, err := v.IsDirty()
+			if err != nil {
+				return err
+			}
+			if dirty {
+				return fmt.Errorf("%s has uncommitted changes, refusing to remove (use force)", v.RepoDir())
+			}
+		}
+
+		if branch := strings.TrimPrefix(v.GetHead(), "refs/heads/"); branch != "" {
+			upstream := v.branchUpstream(branch)
+			if upstream == "" {
+				upstream = "refs/remotes/origin/" + branch
+			}
+			unpushed, err := v.UnpushedCommits(upstream)
+			if err == nil && len(unpushed) > 0 {
+				return fmt.Errorf("%s has %d unpushed commit(s) against %s, refusing to remove (use force)", v.RepoDir(), len(unpushed), upstream)
+			}
+		}
+	}
+
+	if v.DotGit != "" && v.DotGit != v.GitDir {
+		if err := os.RemoveAll(v.DotGit); err != nil {
+			return fmt.Errorf("fail to remove worktree %s: %s", v.DotGit, err)
+		}
+	}
+	if err := os.RemoveAll(v.GitDir); err != nil {
+		return fmt.Errorf("fail to remove gitdir %s: %s", v.GitDir, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package version
+
+// RepoSettings holds per-repository behavior toggles that are not part of
+// the manifest itself, controlling how Repository methods carry out their
+// operations.
+type RepoSettings struct {
+	// PreferGitBinary, when true, skips the go-git backend entirely and
+	// dispatches straight to the git exec backend.
+	PreferGitBinary bool
+
+	// VerifyAfterFetch, when true, runs VerifyPacks after a fetch made by
+	// fetchWithArgs through the git exec backend and reports corrupt packs
+	// instead of leaving silent corruption in place. Fetches made through
+	// the go-git backend, Fetch, or FetchContext are not verified.
+	VerifyAfterFetch bool
+
+	// CheckoutRetries is how many times to retry a worktree file write
+	// during checkout after a transient EBUSY/EACCES error, such as a
+	// file lock held by antivirus scanning on Windows. Zero disables
+	// retrying.
+	CheckoutRetries int
+
+	// SyncOverwriteDirty controls how SyncLocalHalf handles a checkout
+	// that would clobber an uncommitted change.
+	SyncOverwriteDirty DirtyCheckoutMode
+
+	// SymlinkFallback controls what happens when creating a symlink entry
+	// fails, e.g. on Windows without developer mode enabled.
+	SymlinkFallback SymlinkFallbackMode
+
+	// SyncMode selects how SyncLocalHalf reconciles the local branch with
+	// its new upstream tip.
+	SyncMode SyncModeKind
+
+	// InsecureHosts lists remote hosts for which TLS certificate
+	// verification should be disabled (http.<url>.sslVerify=false),
+	// scoped to just those hosts.
+	InsecureHosts []string
+
+	// DisableCloneBundle, when true, skips applyCloneBundle entirely and
+	// goes straight to fetching from the network remote.
+	DisableCloneBundle bool
+
+	// Depth requests a shallow fetch truncated to this many commits.
+	// Zero means unlimited (full history). A later Fetch with a larger
+	// Depth deepens the existing shallow clone.
+	Depth int
+
+	// CloneFilter requests a partial clone filter (e.g. "blob:none",
+	// "blob:limit=1m", "tree:0"), written to
+	// remote.<name>.partialclonefilter and sent as --filter on fetch.
+	// Fetch fails with a clear error if the remote doesn't advertise the
+	// filter capability, rather than silently downloading everything.
+	CloneFilter string
+
+	// LFSEnabled, when true, makes FetchLFS download Git LFS objects
+	// referenced by pointer files at the checked-out revision.
+	LFSEnabled bool
+
+	// ProtectedRefPatterns lists glob patterns (e.g. "refs/heads/main",
+	// "refs/tags/*") that IsProtectedRef matches against to keep important
+	// refs from being deleted by prune/cleanup operations.
+	ProtectedRefPatterns []string
+
+	// FetchJobs sets how many parallel jobs SyncSubmodules passes to
+	// `git submodule update` as --jobs. Fetch and FetchAllRemotes do not
+	// use it. Values less than 1 are treated as 1 (no parallelism).
+	FetchJobs int
+
+	// Mirror, when true, makes setRemote configure a full mirror instead
+	// of a normal working checkout: core.bare stays true, the fetch
+	// refspec becomes "+refs/*:refs/*", and remote.<name>.mirror is set.
+	Mirror bool
+
+	// GitlinkPolicy controls how ApplyGitlinkPolicy handles gitlink
+	// (submodule) tree entries left behind by a non-recursive checkout.
+	GitlinkPolicy GitlinkPolicyKind
+}
+
+// GitlinkPolicyKind selects how a checked-out gitlink entry is handled.
+type GitlinkPolicyKind int
+
+const (
+	// GitlinkEmpty leaves the placeholder directory git already created.
+	GitlinkEmpty GitlinkPolicyKind = iota
+	// GitlinkSkip removes the placeholder directory entirely.
+	GitlinkSkip
+	// GitlinkInit initializes and updates the submodule in place.
+	GitlinkInit
+)
+
+// fetchJobs returns Settings.FetchJobs, defaulting to 1 when Settings is
+// nil or the configured value isn't positive.
+func (v Repository) fetchJobs() int {
+	if v.Settings == nil || v.Settings.FetchJobs < 1 {
+		return 1
+	}
+	return v.Settings.FetchJobs
+}
+
+// SyncModeKind selects the strategy SyncLocalHalf uses to reconcile a local
+// branch with its upstream after fetch.
+type SyncModeKind int
+
+const (
+	// SyncRebase rebases the local branch onto the new upstream tip.
+	SyncRebase SyncModeKind = iota
+	// SyncMerge merges the new upstream tip into the local branch.
+	SyncMerge
+	// SyncFastForwardOnly refuses to update unless the local branch can
+	// fast-forward to the new upstream tip.
+	SyncFastForwardOnly
+)
+
+// SymlinkFallbackMode controls how a symlink checkout/linkfile entry is
+// handled when the platform refuses to create a real symlink.
+type SymlinkFallbackMode int
+
+const (
+	// SymlinkError fails the operation when a symlink cannot be created.
+	SymlinkError SymlinkFallbackMode = iota
+	// SymlinkCopy writes a plain file containing the link target's content.
+	SymlinkCopy
+	// SymlinkSkip omits the entry entirely.
+	SymlinkSkip
+)
+
+// DirtyCheckoutMode controls how a checkout reacts to locally modified
+// tracked files that would otherwise be overwritten.
+type DirtyCheckoutMode int
+
+const (
+	// Abort leaves the dirty file untouched and fails the checkout.
+	Abort DirtyCheckoutMode = iota
+	// Stash stashes the dirty file, performs the checkout, then reapplies
+	// the stash and reports any conflicts.
+	Stash
+	// Discard resets the dirty file to match the checkout target.
+	Discard
+)
+
+// gitBackend identifies which implementation (backendGoGit or backendExec)
+// handled an operation, for the debug log emitted by backend dispatch helpers.
+type gitBackend string
+
+const (
+	backendGoGit gitBackend = "go-git"
+	backendExec  gitBackend = "git-exec"
+)
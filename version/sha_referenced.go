@@ -0,0 +1,19 @@
+package version
+
+// ShaIsReferenced reports whether sha is reachable from any ref, as opposed
+// to merely present in the object store. Servers honoring
+// allowAnySHA1InWant can hand back an object that is not referenced by
+// anything and would later be pruned; callers can pin such an object with a
+// local ref if this returns false.
+func (v Repository) ShaIsReferenced(sha string) (bool, error) {
+	shas, err := v.Revlist("--all")
+	if err != nil {
+		return false, err
+	}
+	for _, s := range shas {
+		if s == sha {
+			return true, nil
+		}
+	}
+	return false, nil
+}
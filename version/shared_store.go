@@ -0,0 +1,31 @@
+package version
+
+import "path/filepath"
+
+// SharesObjectStore reports whether a and b physically share the same
+// object store, following each repository's ObjectsRepository alternate
+// and resolving symlinks so aliased paths compare equal.
+func SharesObjectStore(a, b *Repository) (bool, error) {
+	aDir, err := objectStoreDir(a)
+	if err != nil {
+		return false, err
+	}
+	bDir, err := objectStoreDir(b)
+	if err != nil {
+		return false, err
+	}
+	return aDir == bDir, nil
+}
+
+func objectStoreDir(r *Repository) (string, error) {
+	target := r
+	if shared := r.ObjectsRepository(); shared != nil {
+		target = shared
+	}
+	dir := filepath.Join(target.CommonDir(), "objects")
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return filepath.Clean(dir), nil
+	}
+	return resolved, nil
+}
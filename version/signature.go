@@ -0,0 +1,71 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// VerifyRevisionSignature verifies that revision (a commit or a tag) carries
+// a GPG signature from one of trustedKeys, returning the signer's key ID on
+// success and a detailed error otherwise.
+func (v Repository) VerifyRevisionSignature(revision string, trustedKeys []string) (string, error) {
+	cmd := exec.Command(GIT, "verify-commit", "--raw", revision)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		// Not a commit; try as a tag.
+		cmd = exec.Command(GIT, "verify-tag", "--raw", revision)
+		cmd.Dir = v.RepoDir()
+		out, err = cmd.CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%s is not signed or the signature is invalid: %s", revision, err)
+		}
+	}
+
+	keyID := extractGPGKeyID(string(out))
+	if keyID == "" {
+		return "", fmt.Errorf("%s has a signature but no key ID could be determined", revision)
+	}
+
+	for _, trusted := range trustedKeys {
+		if strings.EqualFold(keyID, trusted) || strings.HasSuffix(keyID, trusted) {
+			return keyID, nil
+		}
+	}
+	return "", fmt.Errorf("%s is signed by untrusted key %s", revision, keyID)
+}
+
+func extractGPGKeyID(raw string) string {
+	for _, line := range strings.Split(raw, "\n") {
+		if strings.Contains(line, "VALIDSIG") {
+			fields := strings.Fields(line)
+			if len(fields) >= 3 {
+				return fields[2]
+			}
+		}
+	}
+	return ""
+}
+
+// VerifyCheckoutSignature verifies that the checked-out HEAD, or the tag it
+// was checked out from, is signed by one of trustedKeys.
+func (v Repository) VerifyCheckoutSignature(trustedKeys []string) error {
+	_, err := v.VerifyRevisionSignature("HEAD", trustedKeys)
+	if err == nil {
+		return nil
+	}
+
+	cmd := exec.Command(GIT, "tag", "--points-at", "HEAD")
+	cmd.Dir = v.RepoDir()
+	out, tagErr := cmd.Output()
+	if tagErr != nil {
+		return err
+	}
+	for _, tag := range strings.Fields(string(out)) {
+		if _, tagErr := v.VerifyRevisionSignature("refs/tags/"+tag, trustedKeys); tagErr == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// ErrNoSignature is returned by VerifyRevision when the resolved object
+// (tag or commit) carries no PGP signature at all, distinguishing "nothing
+// to verify" from a verification failure.
+var ErrNoSignature = errors.New("object has no PGP signature")
+
+// SignatureResult reports the outcome of verifying a signed tag or commit.
+type SignatureResult struct {
+	Signer string
+	Valid  bool
+}
+
+// VerifyRevision resolves revision through v.Raw(), then verifies its
+// embedded PGP signature against keyring. An annotated tag's own signature
+// is verified; a lightweight tag falls back to verifying the commit it
+// points at. Objects with no signature produce ErrNoSignature.
+//
+// Unlike VerifyRevisionSignature (which shells out to `git verify-commit`/
+// `verify-tag` against the caller's configured trust store), this verifies
+// directly against an in-memory keyring, for callers that manage their own
+// signer allowlist rather than relying on gpg's.
+func (v Repository) VerifyRevision(revision string, keyring openpgp.KeyRing) (*SignatureResult, error) {
+	raw := v.Raw()
+	if raw == nil {
+		return nil, fmt.Errorf("fail to open git repo '%s'", v.RepoDir())
+	}
+
+	hash, err := raw.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("fail to resolve %s: %s", revision, err)
+	}
+
+	// ResolveRevision peels annotated tags to their commit, so look the name
+	// up as a tag first to reach the tag object itself.
+	objHash := *hash
+	if ref, err := raw.Tag(strings.TrimPrefix(revision, "refs/tags/")); err == nil {
+		objHash = ref.Hash()
+	}
+
+	var signature string
+	var signed signedObject
+	if tag, tagErr := raw.TagObject(objHash); tagErr == nil {
+		signature = tag.PGPSignature
+		signed = tag
+	} else {
+		commit, err := raw.CommitObject(*hash)
+		if err != nil {
+			return nil, fmt.Errorf("fail to load %s as tag or commit: %s", revision, err)
+		}
+		signature = commit.PGPSignature
+		signed = commit
+	}
+
+	if signature == "" {
+		return nil, ErrNoSignature
+	}
+
+	payload, err := signaturePayload(signed)
+	if err != nil {
+		return nil, fmt.Errorf("fail to encode %s for verification: %s", revision, err)
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, payload, strings.NewReader(signature))
+	if err != nil {
+		return &SignatureResult{Valid: false}, fmt.Errorf("fail to verify signature on %s: %s", revision, err)
+	}
+
+	var name string
+	for id := range signer.Identities {
+		name = id
+		break
+	}
+	return &SignatureResult{Signer: name, Valid: true}, nil
+}
+
+// signedObject is implemented by go-git's *object.Commit and *object.Tag,
+// which both know how to re-encode themselves without their PGPSignature
+// field, reproducing the exact bytes that were signed.
+type signedObject interface {
+	EncodeWithoutSignature(o plumbing.EncodedObject) error
+}
+
+// signaturePayload returns the exact byte stream that was PGP-signed for
+// signed, by re-encoding it with its signature field stripped, matching
+// what `git verify-commit`/`verify-tag` check against. Using .String()
+// instead (a human-readable log rendering) would fail every genuinely
+// signed object.
+func signaturePayload(signed signedObject) (io.Reader, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := signed.EncodeWithoutSignature(encoded); err != nil {
+		return nil, err
+	}
+	return encoded.Reader()
+}
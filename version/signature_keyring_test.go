@@ -0,0 +1,80 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+func newSignedTestRepo(t *testing.T, entity *openpgp.Entity) (*Repository, string) {
+	t.Helper()
+
+	fs := memfs.New()
+	rawRepo, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatalf("git.Init: %s", err)
+	}
+
+	f, err := fs.Create("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	w, err := rawRepo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	sig := &object.Signature{Name: "Tester", Email: "tester@example.com", When: time.Now()}
+	hash, err := w.Commit("initial commit", &git.CommitOptions{Author: sig, SignKey: entity})
+	if err != nil {
+		t.Fatalf("Commit: %s", err)
+	}
+
+	repo := &Repository{}
+	repo.raw = rawRepo
+	return repo, hash.String()
+}
+
+func TestVerifyRevisionValidSignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("Tester", "", "tester@example.com", nil)
+	if err != nil {
+		t.Fatalf("NewEntity: %s", err)
+	}
+
+	repo, sha := newSignedTestRepo(t, entity)
+
+	result, err := repo.VerifyRevision(sha, openpgp.EntityList{entity})
+	if err != nil {
+		t.Fatalf("VerifyRevision: %s", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected a valid signature")
+	}
+}
+
+func TestVerifyRevisionNoSignature(t *testing.T) {
+	repo, sha := newSignedTestRepo(t, nil)
+
+	entity, err := openpgp.NewEntity("Tester", "", "tester@example.com", nil)
+	if err != nil {
+		t.Fatalf("NewEntity: %s", err)
+	}
+
+	_, err = repo.VerifyRevision(sha, openpgp.EntityList{entity})
+	if err != ErrNoSignature {
+		t.Errorf("err = %v, want ErrNoSignature", err)
+	}
+}
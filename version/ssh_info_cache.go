@@ -0,0 +1,50 @@
+package version
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// SSHInfo holds the cached ssh protocol settings for a host, e.g. as
+// discovered from a Gerrit server's /ssh_info endpoint.
+type SSHInfo struct {
+	Host string
+	Port int
+}
+
+type sshInfoCacheEntry struct {
+	Info     SSHInfo       `json:"info"`
+	CachedAt time.Time     `json:"cached_at"`
+	TTL      time.Duration `json:"ttl"`
+}
+
+// LoadSSHInfo reads the cached SSHInfo written by SaveSSHInfo. It returns
+// ok=false, with no error, for a missing, corrupt, or expired cache file —
+// callers should treat all three the same way: go fetch fresh info.
+func (v Repository) LoadSSHInfo() (*SSHInfo, bool) {
+	data, err := os.ReadFile(v.SSHInfoCacheFile())
+	if err != nil {
+		return nil, false
+	}
+
+	var entry sshInfoCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if entry.TTL > 0 && time.Since(entry.CachedAt) > entry.TTL {
+		return nil, false
+	}
+	return &entry.Info, true
+}
+
+// SaveSSHInfo writes info to the cache file alongside the current time and
+// ttl, so a later LoadSSHInfo can tell whether it has gone stale.
+func (v Repository) SaveSSHInfo(info *SSHInfo, ttl time.Duration) error {
+	entry := sshInfoCacheEntry{Info: *info, CachedAt: time.Now(), TTL: ttl}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(v.SSHInfoCacheFile(), data, 0644)
+}
@@ -0,0 +1,70 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// StashEntry describes one entry in the repository's stash list.
+type StashEntry struct {
+	Index   int
+	Branch  string
+	Message string
+}
+
+// StashList returns all stash entries, most recent first, as reported by
+// `git stash list`.
+func (v Repository) StashList() ([]StashEntry, error) {
+	cmd := exec.Command(GIT, "stash", "list", "--format=%gd%x09%gs")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list stash: %s", err)
+	}
+
+	var entries []StashEntry
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		ref := strings.TrimSuffix(strings.TrimPrefix(fields[0], "stash@{"), "}")
+		idx, err := strconv.Atoi(ref)
+		if err != nil {
+			continue
+		}
+		msg := fields[1]
+		branch := ""
+		if strings.HasPrefix(msg, "On ") {
+			if colon := strings.Index(msg, ":"); colon > 0 {
+				branch = msg[len("On "):colon]
+			}
+		}
+		entries = append(entries, StashEntry{Index: idx, Branch: branch, Message: msg})
+	}
+	return entries, nil
+}
+
+// StashApply applies the stash entry at index without removing it.
+func (v Repository) StashApply(index int) error {
+	return v.runStash("apply", fmt.Sprintf("stash@{%d}", index))
+}
+
+// StashDrop removes the stash entry at index without applying it.
+func (v Repository) StashDrop(index int) error {
+	return v.runStash("drop", fmt.Sprintf("stash@{%d}", index))
+}
+
+func (v Repository) runStash(action string, args ...string) error {
+	cmd := exec.Command(GIT, append([]string{"stash", action}, args...)...)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to %s stash: %s: %s", action, err, out)
+	}
+	return nil
+}
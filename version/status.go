@@ -0,0 +1,30 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FileIsModified reports whether the worktree copy of path differs from its
+// index entry, using git's own stat-then-content comparison. It returns a
+// "not tracked" error for paths that are not tracked in the index.
+func (v Repository) FileIsModified(path string) (bool, error) {
+	cmd := exec.Command(GIT, "diff-files", "--name-only", "--", path)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("fail to diff %s: %s", path, err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		return true, nil
+	}
+
+	cmd = exec.Command(GIT, "ls-files", "--error-unmatch", "--", path)
+	cmd.Dir = v.RepoDir()
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Errorf("%s: not tracked", path)
+	}
+
+	return false, nil
+}
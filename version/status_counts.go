@@ -0,0 +1,46 @@
+package version
+
+import "errors"
+
+// ErrBareWorktree is returned by StatusCounts for a bare repository, which
+// has no worktree to compute status against.
+var ErrBareWorktree = errors.New("repository is bare, has no worktree status")
+
+// StatusCounts returns quick summary counts of the worktree status: staged,
+// unstaged, untracked, and conflicted entries, computed from a single
+// worktree status pass.
+func (v Repository) StatusCounts() (staged, unstaged, untracked, conflicted int, err error) {
+	if v.IsBare {
+		return 0, 0, 0, 0, ErrBareWorktree
+	}
+
+	raw := v.Raw()
+	if raw == nil {
+		return 0, 0, 0, 0, errors.New("fail to open repository")
+	}
+	wt, err := raw.Worktree()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	st, err := wt.Status()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	for _, entry := range st {
+		switch {
+		case entry.Staging == '?' && entry.Worktree == '?':
+			untracked++
+		case entry.Staging == 'U' || entry.Worktree == 'U':
+			conflicted++
+		default:
+			if entry.Staging != ' ' && entry.Staging != '?' {
+				staged++
+			}
+			if entry.Worktree != ' ' && entry.Worktree != '?' {
+				unstaged++
+			}
+		}
+	}
+	return staged, unstaged, untracked, conflicted, nil
+}
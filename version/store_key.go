@@ -0,0 +1,22 @@
+package version
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+// ProjectStoreKey produces the deterministic directory name used for a
+// project's shared object store (ObjectsGitDir), derived from its remote
+// URL and name. Equivalent URL forms (trailing slash, ".git" suffix)
+// normalize to the same key.
+func ProjectStoreKey(remoteURL, name string) string {
+	normalized := strings.TrimSuffix(strings.TrimSuffix(remoteURL, "/"), ".git")
+
+	h := sha1.New()
+	h.Write([]byte(normalized))
+	h.Write([]byte{0})
+	h.Write([]byte(name))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
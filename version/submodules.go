@@ -0,0 +1,103 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Submodule describes one entry parsed out of .gitmodules.
+type Submodule struct {
+	Name   string
+	Path   string
+	URL    string
+	Branch string
+}
+
+// maxSubmoduleDepth is the limit syncSubmodules checks its depth argument
+// against. SyncSubmodules always passes a depth of 0 and syncSubmodules
+// does not call itself, so the limit is never reached.
+const maxSubmoduleDepth = 5
+
+// Submodules parses .gitmodules at HEAD and returns the submodules it
+// declares. A missing .gitmodules is not an error; it just means none.
+func (v Repository) Submodules() ([]Submodule, error) {
+	cmd := exec.Command(GIT, "show", "HEAD:.gitmodules")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil
+	}
+
+	var subs []Submodule
+	var cur *Submodule
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[submodule ") {
+			if cur != nil {
+				subs = append(subs, *cur)
+			}
+			name := strings.Trim(strings.TrimPrefix(line, "[submodule "), `"]`)
+			cur = &Submodule{Name: name}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "path":
+			cur.Path = value
+		case "url":
+			cur.URL = value
+		case "branch":
+			cur.Branch = value
+		}
+	}
+	if cur != nil {
+		subs = append(subs, *cur)
+	}
+	return subs, nil
+}
+
+// SyncSubmodules clones or updates every submodule reported by Submodules
+// with `git submodule update --init`, passing opts.Depth as --depth and
+// RepoSettings.FetchJobs as --jobs. No reference repository or partial
+// clone filter is applied, and nested submodules are not updated. It
+// refuses to run on a bare repository, which has no worktree to populate.
+func (v Repository) SyncSubmodules(opts FetchOptions) error {
+	return v.syncSubmodules(opts, 0)
+}
+
+func (v Repository) syncSubmodules(opts FetchOptions, depth int) error {
+	if v.IsBare {
+		return fmt.Errorf("cannot sync submodules on bare repository %s", v.RepoDir())
+	}
+	if depth >= maxSubmoduleDepth {
+		return fmt.Errorf("submodule recursion in %s exceeded depth %d, aborting", v.RepoDir(), maxSubmoduleDepth)
+	}
+
+	subs, err := v.Submodules()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		args := []string{"submodule", "update", "--init", "--jobs", fmt.Sprintf("%d", v.fetchJobs())}
+		if opts.Depth > 0 {
+			args = append(args, "--depth", fmt.Sprintf("%d", opts.Depth))
+		}
+		args = append(args, "--", sub.Path)
+		cmd := exec.Command(GIT, args...)
+		cmd.Dir = v.RepoDir()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("fail to sync submodule %s: %s: %s", sub.Path, err, out)
+		}
+	}
+	return nil
+}
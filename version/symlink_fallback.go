@@ -0,0 +1,42 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// createSymlinkWithFallback creates a symlink at linkPath pointing at
+// target, falling back to RepoSettings.SymlinkFallback when the platform
+// refuses to create real symlinks (e.g. Windows without developer mode).
+func (v Repository) createSymlinkWithFallback(linkPath, target string) error {
+	err := os.Symlink(target, linkPath)
+	if err == nil {
+		return nil
+	}
+	if !os.IsPermission(err) {
+		return err
+	}
+
+	mode := SymlinkError
+	if v.Settings != nil {
+		mode = v.Settings.SymlinkFallback
+	}
+
+	switch mode {
+	case SymlinkCopy:
+		src := target
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(filepath.Dir(linkPath), src)
+		}
+		data, readErr := os.ReadFile(src)
+		if readErr != nil {
+			return fmt.Errorf("fail to copy symlink target %s to %s: %s", src, linkPath, readErr)
+		}
+		return os.WriteFile(linkPath, data, 0644)
+	case SymlinkSkip:
+		return nil
+	default:
+		return fmt.Errorf("fail to create symlink %s -> %s: %s", linkPath, target, err)
+	}
+}
@@ -0,0 +1,121 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SyncLocalHalf checks out revision, applying RepoSettings.SyncOverwriteDirty
+// to decide what happens to any locally modified tracked file that the
+// checkout would otherwise clobber. The default mode, Abort, is the safe
+// choice: leave the dirty file alone and fail rather than silently lose
+// local work.
+//
+// When the current branch already exists and has local commits on top of
+// its old upstream, RepoSettings.SyncMode selects how it is reconciled with
+// revision: SyncRebase (default) replays local commits on top of revision,
+// SyncMerge merges revision in, and SyncFastForwardOnly refuses to update
+// unless the branch can fast-forward. Conflicts from SyncRebase/SyncMerge
+// are reported via
+// ConflictedFiles.
+func (v Repository) SyncLocalHalf(revision string) error {
+	overwrite := Abort
+	if v.Settings != nil {
+		overwrite = v.Settings.SyncOverwriteDirty
+	}
+
+	switch overwrite {
+	case Stash:
+		if err := v.runStash("push"); err != nil {
+			return err
+		}
+		if err := v.reconcileWithUpstream(revision); err != nil {
+			return err
+		}
+		if err := v.runStash("pop"); err != nil {
+			return fmt.Errorf("checkout succeeded but stash pop reported conflicts: %s", err)
+		}
+		return nil
+	case Discard:
+		if err := v.resetHard(); err != nil {
+			return err
+		}
+		return v.checkout(revision)
+	default:
+		return v.reconcileWithUpstream(revision)
+	}
+}
+
+// reconcileWithUpstream brings the current branch up to date with revision
+// using the configured SyncMode. If HEAD is detached (nothing to reconcile
+// onto), it just checks out revision.
+func (v Repository) reconcileWithUpstream(revision string) error {
+	if v.IsUnborn() {
+		return v.initialCheckout(revision)
+	}
+
+	head := v.GetHead()
+	if head == "" {
+		return v.checkout(revision)
+	}
+
+	mode := SyncRebase
+	if v.Settings != nil {
+		mode = v.Settings.SyncMode
+	}
+
+	switch mode {
+	case SyncMerge:
+		return v.runGit("merge", revision)
+	case SyncFastForwardOnly:
+		return v.runGit("merge", "--ff-only", revision)
+	default:
+		return v.runGit("rebase", revision)
+	}
+}
+
+func (v Repository) runGit(args ...string) error {
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to run git %s: %s: %s", args[0], err, out)
+	}
+	return nil
+}
+
+// ConflictedFiles returns the paths currently marked unmerged in the index
+// after a failed rebase or merge.
+func (v Repository) ConflictedFiles() ([]string, error) {
+	cmd := exec.Command(GIT, "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+func (v Repository) checkout(revision string) error {
+	cmd := exec.Command(GIT, "checkout", revision)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to checkout %s: %s: %s", revision, err, out)
+	}
+	return nil
+}
+
+func (v Repository) resetHard() error {
+	cmd := exec.Command(GIT, "reset", "--hard")
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to reset: %s: %s", err, out)
+	}
+	return nil
+}
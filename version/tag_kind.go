@@ -0,0 +1,28 @@
+package version
+
+import (
+	"fmt"
+)
+
+// TagKind reports whether tagName is an "annotated" or "lightweight" tag,
+// so upload/release tooling can enforce annotated tags where required.
+func (v Repository) TagKind(tagName string) (string, error) {
+	raw := v.Raw()
+	if raw == nil {
+		return "", fmt.Errorf("cannot open repository")
+	}
+
+	ref, err := raw.Tag(tagName)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a tag: %s", tagName, err)
+	}
+
+	if _, err := raw.TagObject(ref.Hash()); err == nil {
+		return "annotated", nil
+	}
+	if _, err := raw.CommitObject(ref.Hash()); err == nil {
+		return "lightweight", nil
+	}
+
+	return "", fmt.Errorf("%s does not resolve to a commit or annotated tag object", tagName)
+}
@@ -0,0 +1,28 @@
+package version
+
+import (
+	"regexp"
+
+	log "github.com/jiangxin/multi-log"
+)
+
+var revisionTemplateVar = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// ResolveRevisionTemplate substitutes ${var} tokens in Revision with values
+// from vars, so a manifest can parameterize revisions (e.g. a release
+// number) before the revision is resolved against the remote. Tokens with
+// no matching entry in vars are left intact and logged as a warning.
+func (v Repository) ResolveRevisionTemplate(vars map[string]string) string {
+	return expandRevisionTemplate(v.Revision, vars)
+}
+
+func expandRevisionTemplate(tmpl string, vars map[string]string) string {
+	return revisionTemplateVar.ReplaceAllStringFunc(tmpl, func(token string) string {
+		name := token[2 : len(token)-1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		log.Warnf("revision template variable %q has no substitution", name)
+		return token
+	})
+}
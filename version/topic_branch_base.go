@@ -0,0 +1,70 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// TopicBranchBase returns the merge-base shared by every local branch other
+// than the tracking branch reported by GetHead, plus the tracking branch
+// itself, so tooling can show how far a set of topic branches have
+// collectively diverged. With a single branch (or none but the tracking
+// branch), it returns that branch's tip.
+func (v Repository) TopicBranchBase() (string, error) {
+	tracking := v.GetHead()
+	if tracking == "" {
+		return "", fmt.Errorf("fail to determine current branch in %s", v.RepoDir())
+	}
+
+	branches, err := v.localBranches()
+	if err != nil {
+		return "", err
+	}
+
+	revs := []string{tracking}
+	for _, branch := range branches {
+		if branch != tracking {
+			revs = append(revs, branch)
+		}
+	}
+	if len(revs) == 1 {
+		return v.revParse(revs[0])
+	}
+
+	args := append([]string{"merge-base", "--octopus"}, revs...)
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("fail to compute merge-base of %s: %s", strings.Join(revs, ", "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (v Repository) localBranches() ([]string, error) {
+	cmd := exec.Command(GIT, "for-each-ref", "--format=%(refname)", "refs/heads")
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to list local branches in %s: %s", v.RepoDir(), err)
+	}
+
+	var branches []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+func (v Repository) revParse(rev string) (string, error) {
+	cmd := exec.Command(GIT, "rev-parse", rev)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("fail to resolve %s: %s", rev, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
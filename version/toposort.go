@@ -0,0 +1,79 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dependsOnAnnotation is the manifest annotation name whose value lists the
+// projects a project depends on, as a comma-separated list of project names.
+const dependsOnAnnotation = "depends-on"
+
+// dependsOn returns the project names listed in r's "depends-on" manifest
+// annotation, if any.
+func dependsOn(r *Repository) []string {
+	var deps []string
+	for _, a := range r.Annotations {
+		if a.Name != dependsOnAnnotation {
+			continue
+		}
+		for _, dep := range strings.Split(a.Value, ",") {
+			dep = strings.TrimSpace(dep)
+			if dep != "" {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// TopoSortByDependency orders repos so that every project appears after the
+// projects listed in its manifest "depends-on" annotation, letting sync
+// build dependencies first. It returns an error if the dependency graph has
+// a cycle.
+func TopoSortByDependency(repos []*Repository) ([]*Repository, error) {
+	byName := make(map[string]*Repository, len(repos))
+	for _, r := range repos {
+		byName[r.Name] = r
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(repos))
+	var order []*Repository
+
+	var visit func(r *Repository) error
+	visit = func(r *Repository) error {
+		switch state[r.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at project %s", r.Name)
+		}
+		state[r.Name] = visiting
+
+		for _, dep := range dependsOn(r) {
+			depRepo, ok := byName[dep]
+			if !ok {
+				continue
+			}
+			if err := visit(depRepo); err != nil {
+				return err
+			}
+		}
+
+		state[r.Name] = visited
+		order = append(order, r)
+		return nil
+	}
+
+	for _, r := range repos {
+		if err := visit(r); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,76 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CommitInfo holds the pieces of a commit that callers most commonly need,
+// without requiring a full go-git commit object.
+type CommitInfo struct {
+	SHA     string
+	Subject string
+	Body    string
+}
+
+// commitInfo loads subject and body for revision.
+func (v Repository) commitInfo(revision string) (CommitInfo, error) {
+	cmd := exec.Command(GIT, "show", "-s", "--format=%H%n%s%n%b", revision)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return CommitInfo{}, fmt.Errorf("fail to read commit %s: %s", revision, err)
+	}
+
+	lines := strings.SplitN(string(out), "\n", 3)
+	info := CommitInfo{SHA: lines[0]}
+	if len(lines) > 1 {
+		info.Subject = lines[1]
+	}
+	if len(lines) > 2 {
+		info.Body = lines[2]
+	}
+	return info, nil
+}
+
+// CommitTrailers parses the git trailers (e.g. Change-Id, Signed-off-by)
+// out of revision's commit body, folding continuation lines and collecting
+// multiple values per key in the order they appear.
+func (v Repository) CommitTrailers(revision string) (map[string][]string, error) {
+	info, err := v.commitInfo(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	trailers := make(map[string][]string)
+	var lastKey string
+
+	for _, line := range strings.Split(info.Body, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && lastKey != "" {
+			// Folded continuation of the previous trailer's value.
+			last := len(trailers[lastKey]) - 1
+			trailers[lastKey][last] += " " + strings.TrimSpace(line)
+			continue
+		}
+
+		colon := strings.IndexByte(line, ':')
+		if colon <= 0 {
+			lastKey = ""
+			continue
+		}
+		key := strings.TrimSpace(line[:colon])
+		if strings.ContainsAny(key, " \t") {
+			lastKey = ""
+			continue
+		}
+		value := strings.TrimSpace(line[colon+1:])
+		trailers[key] = append(trailers[key], value)
+		lastKey = key
+	}
+
+	return trailers, nil
+}
@@ -0,0 +1,23 @@
+package version
+
+import "fmt"
+
+// IsUnborn reports whether HEAD has no commit yet, as is the case right
+// after `git init` before the first checkout.
+func (v Repository) IsUnborn() bool {
+	return v.isUnborn()
+}
+
+// initialCheckout creates the tracking branch at revision and checks it
+// out, for the unborn-HEAD case where there is no existing branch to
+// rebase or merge onto.
+func (v Repository) initialCheckout(revision string) error {
+	branch := v.DefaultTrackingBranch()
+	if branch == "" {
+		return v.checkout(revision)
+	}
+	if err := v.runGit("checkout", "-b", branch, revision); err != nil {
+		return fmt.Errorf("fail to create initial branch %s at %s: %s", branch, revision, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+)
+
+// RefUpdateKind selects the operation a RefUpdate performs.
+type RefUpdateKind int
+
+const (
+	// RefCreate creates or unconditionally updates a ref to NewValue.
+	RefCreate RefUpdateKind = iota
+	// RefUpdateCAS updates a ref to NewValue only if it currently holds
+	// OldValue.
+	RefUpdateCAS
+	// RefDelete removes a ref, optionally requiring it currently holds
+	// OldValue.
+	RefDelete
+)
+
+// RefUpdate describes one ref change to apply as part of an atomic batch.
+type RefUpdate struct {
+	Kind     RefUpdateKind
+	Ref      string
+	NewValue string
+	OldValue string
+}
+
+// UpdateRefs applies updates atomically via `git update-ref --stdin -z`: if
+// any compare-and-swap check fails, the entire batch is rolled back and no
+// update is applied.
+func (v *Repository) UpdateRefs(updates []RefUpdate) error {
+	var buf bytes.Buffer
+	for _, u := range updates {
+		switch u.Kind {
+		case RefDelete:
+			fmt.Fprintf(&buf, "delete %s\x00%s\x00", u.Ref, u.OldValue)
+		case RefUpdateCAS:
+			fmt.Fprintf(&buf, "update %s\x00%s\x00%s\x00", u.Ref, u.NewValue, u.OldValue)
+		default:
+			fmt.Fprintf(&buf, "update %s\x00%s\x00\x00", u.Ref, u.NewValue)
+		}
+	}
+
+	cmd := exec.Command(GIT, "update-ref", "--stdin", "-z")
+	cmd.Dir = v.RepoDir()
+	cmd.Stdin = &buf
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to apply ref update transaction: %s: %s", err, out)
+	}
+	return nil
+}
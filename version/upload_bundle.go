@@ -0,0 +1,39 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// UnpushedCommits returns the commits reachable from HEAD but not from
+// upstreamRef, i.e. the commits an upload would send.
+func (v Repository) UnpushedCommits(upstreamRef string) ([]string, error) {
+	return v.Revlist(upstreamRef + "..HEAD")
+}
+
+// CreateBundle writes a git bundle to outPath containing the given
+// revision range, and verifies it before returning.
+func (v Repository) CreateBundle(outPath string, revs ...string) error {
+	args := append([]string{"bundle", "create", outPath}, revs...)
+	cmd := exec.Command(GIT, args...)
+	cmd.Dir = v.RepoDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("fail to create bundle %s: %s: %s", outPath, err, out)
+	}
+
+	verify := exec.Command(GIT, "bundle", "verify", outPath)
+	verify.Dir = v.RepoDir()
+	if out, err := verify.CombinedOutput(); err != nil {
+		return fmt.Errorf("bundle %s failed verification: %s: %s", outPath, err, out)
+	}
+	return nil
+}
+
+// CreateUploadBundle bundles only the upload delta: commits reachable from
+// HEAD but not from upstreamRef, for air-gapped review.
+func (v Repository) CreateUploadBundle(outPath, upstreamRef string) error {
+	if _, err := v.UnpushedCommits(upstreamRef); err != nil {
+		return fmt.Errorf("fail to compute upload delta: %s", err)
+	}
+	return v.CreateBundle(outPath, upstreamRef+"..HEAD")
+}
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommitRules configures what ValidateUploadCommits checks for.
+type CommitRules struct {
+	MaxSubjectLen    int
+	RequireChangeId  bool
+	RequireSignedOff bool
+	ForbidWIP        bool
+}
+
+// CommitViolation records one commit failing one rule.
+type CommitViolation struct {
+	SHA     string
+	Subject string
+	Rule    string
+}
+
+// ValidateUploadCommits checks every commit reachable from HEAD but not
+// from upstreamRef against rules, returning one CommitViolation per broken
+// rule per commit.
+func (v Repository) ValidateUploadCommits(upstreamRef string, rules CommitRules) ([]CommitViolation, error) {
+	shas, err := v.UnpushedCommits(upstreamRef)
+	if err != nil {
+		return nil, fmt.Errorf("fail to compute upload delta: %s", err)
+	}
+
+	var violations []CommitViolation
+	for _, sha := range shas {
+		info, err := v.commitInfo(sha)
+		if err != nil {
+			return nil, err
+		}
+
+		if rules.MaxSubjectLen > 0 && len(info.Subject) > rules.MaxSubjectLen {
+			violations = append(violations, CommitViolation{SHA: info.SHA, Subject: info.Subject, Rule: "MaxSubjectLen"})
+		}
+		if rules.ForbidWIP && strings.Contains(strings.ToUpper(info.Subject), "WIP") {
+			violations = append(violations, CommitViolation{SHA: info.SHA, Subject: info.Subject, Rule: "ForbidWIP"})
+		}
+
+		trailers, err := v.CommitTrailers(sha)
+		if err != nil {
+			return nil, err
+		}
+		if rules.RequireChangeId && len(trailers["Change-Id"]) == 0 {
+			violations = append(violations, CommitViolation{SHA: info.SHA, Subject: info.Subject, Rule: "RequireChangeId"})
+		}
+		if rules.RequireSignedOff && len(trailers["Signed-off-by"]) == 0 {
+			violations = append(violations, CommitViolation{SHA: info.SHA, Subject: info.Subject, Rule: "RequireSignedOff"})
+		}
+	}
+	return violations, nil
+}
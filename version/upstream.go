@@ -0,0 +1,71 @@
+package version
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// MissingUpstreamPolicy controls how HandleMissingUpstream reacts when the
+// manifest-pinned revision no longer exists on the remote.
+type MissingUpstreamPolicy int
+
+const (
+	// Fail returns an error when the upstream revision is missing.
+	Fail MissingUpstreamPolicy = iota
+	// KeepLocal leaves the current checkout untouched.
+	KeepLocal
+	// FallbackToDefault checks out ManifestDefaultRevision instead.
+	FallbackToDefault
+)
+
+// HandleMissingUpstream checks whether the project's remote revision still
+// exists, and applies policy if it does not.
+func (v Repository) HandleMissingUpstream(policy MissingUpstreamPolicy) error {
+	revision := v.Revision
+	remoteURL := v.RemoteURL
+
+	refs, err := v.LsRemote(remoteURL, revision)
+	if err != nil {
+		return fmt.Errorf("fail to check upstream revision %s: %s", revision, err)
+	}
+	if len(refs) > 0 {
+		// Upstream revision still exists, nothing to do.
+		return nil
+	}
+
+	switch policy {
+	case KeepLocal:
+		return nil
+	case FallbackToDefault:
+		if v.ManifestDefaultRevision == "" {
+			return fmt.Errorf("upstream revision %s is missing and no default revision is set", revision)
+		}
+		cmd := exec.Command(GIT, "checkout", v.ManifestDefaultRevision)
+		cmd.Dir = v.WorktreePath()
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("fail to checkout default revision %s: %s: %s", v.ManifestDefaultRevision, err, out)
+		}
+		return nil
+	default:
+		return fmt.Errorf("upstream revision %s no longer exists on %s", revision, remoteURL)
+	}
+}
+
+// LsRemote lists refs on remoteURL matching pattern using `git ls-remote`.
+func (v Repository) LsRemote(remoteURL, pattern string) ([]string, error) {
+	cmd := exec.Command(GIT, "ls-remote", remoteURL, pattern)
+	cmd.Dir = v.RepoDir()
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			refs = append(refs, line)
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,79 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newUpstreamTestRepo(t *testing.T) (remoteDir string, local *Repository) {
+	t.Helper()
+
+	remoteDir = filepath.Join(t.TempDir(), "remote")
+	if err := os.MkdirAll(remoteDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, remoteDir, "init")
+	runGit(t, remoteDir, "config", "user.email", "test@example.com")
+	runGit(t, remoteDir, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(remoteDir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, remoteDir, "add", "a.txt")
+	runGit(t, remoteDir, "commit", "-m", "initial")
+	runGit(t, remoteDir, "branch", "stable")
+
+	localDir := filepath.Join(t.TempDir(), "local")
+	runGit(t, "", "clone", remoteDir, localDir)
+	runGit(t, localDir, "config", "user.email", "test@example.com")
+	runGit(t, localDir, "config", "user.name", "test")
+
+	localGitDir := filepath.Join(localDir, ".git")
+	local = &Repository{GitDir: localGitDir, DotGit: localGitDir, RemoteURL: remoteDir}
+	local.Revision = "master"
+	local.ManifestDefaultRevision = "stable"
+	return remoteDir, local
+}
+
+func TestHandleMissingUpstreamRevisionPresent(t *testing.T) {
+	_, repo := newUpstreamTestRepo(t)
+
+	if err := repo.HandleMissingUpstream(Fail); err != nil {
+		t.Errorf("HandleMissingUpstream: %s", err)
+	}
+}
+
+func TestHandleMissingUpstreamFail(t *testing.T) {
+	_, repo := newUpstreamTestRepo(t)
+	repo.Revision = "does-not-exist"
+
+	if err := repo.HandleMissingUpstream(Fail); err == nil {
+		t.Errorf("expected an error for a missing upstream revision")
+	}
+}
+
+func TestHandleMissingUpstreamKeepLocal(t *testing.T) {
+	_, repo := newUpstreamTestRepo(t)
+	repo.Revision = "does-not-exist"
+
+	if err := repo.HandleMissingUpstream(KeepLocal); err != nil {
+		t.Errorf("HandleMissingUpstream: %s", err)
+	}
+}
+
+func TestHandleMissingUpstreamFallbackToDefault(t *testing.T) {
+	_, repo := newUpstreamTestRepo(t)
+	repo.Revision = "does-not-exist"
+
+	if err := repo.HandleMissingUpstream(FallbackToDefault); err != nil {
+		t.Fatalf("HandleMissingUpstream: %s", err)
+	}
+
+	head, err := repo.LsRemote(repo.RepoDir(), "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(head) == 0 {
+		t.Fatal("expected a resolvable HEAD after checking out the default revision")
+	}
+}
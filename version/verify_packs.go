@@ -0,0 +1,46 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyPacks runs `git verify-pack` against every pack file in the
+// repository's object store and returns the names of any packs that fail
+// verification.
+func (v Repository) VerifyPacks() ([]string, error) {
+	packDir := filepath.Join(v.CommonDir(), "objects", "pack")
+
+	entries, err := os.ReadDir(packDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var corrupt []string
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".idx") {
+			continue
+		}
+		cmd := exec.Command(GIT, "verify-pack", "-v", filepath.Join(packDir, e.Name()))
+		cmd.Dir = v.RepoDir()
+		if err := cmd.Run(); err != nil {
+			corrupt = append(corrupt, e.Name())
+		}
+	}
+	return corrupt, nil
+}
+
+// verifyAfterFetch runs VerifyPacks when RepoSettings.VerifyAfterFetch is
+// enabled, surfacing pack corruption right after a fetch instead of letting
+// it surface later as an opaque object-read failure.
+func (v Repository) verifyAfterFetch() ([]string, error) {
+	if v.Settings == nil || !v.Settings.VerifyAfterFetch {
+		return nil, nil
+	}
+	return v.VerifyPacks()
+}
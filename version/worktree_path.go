@@ -0,0 +1,91 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorktreePath returns the filesystem path of this project's worktree.
+func (v Repository) WorktreePath() string {
+	if v.DotGit != "" {
+		return filepath.Dir(v.DotGit)
+	}
+	return v.Path
+}
+
+// SetWorktreePath relocates the worktree registration to newPath: it
+// validates newPath is writable, rewrites the .git pointer and the shared
+// repository's linked-worktree back-pointer, and updates Path/DotGit.
+func (v *Repository) SetWorktreePath(newPath string) error {
+	if err := os.MkdirAll(newPath, 0755); err != nil {
+		return fmt.Errorf("cannot create worktree path %s: %s", newPath, err)
+	}
+	probe := filepath.Join(newPath, ".repo-write-check")
+	if err := os.WriteFile(probe, nil, 0644); err != nil {
+		return fmt.Errorf("worktree path %s is not writable: %s", newPath, err)
+	}
+	os.Remove(probe)
+
+	oldDotGit := v.DotGit
+	newDotGit := filepath.Join(newPath, ".git")
+	if err := os.WriteFile(newDotGit, []byte("gitdir: "+v.GitDir+"\n"), 0644); err != nil {
+		return fmt.Errorf("fail to write %s: %s", newDotGit, err)
+	}
+
+	if oldDotGit != "" {
+		backPointer, err := findWorktreeGitdirFile(v.GitDir, oldDotGit)
+		if err != nil {
+			return err
+		}
+		if backPointer != "" {
+			absDotGit, err := filepath.Abs(newDotGit)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(backPointer, []byte(absDotGit+"\n"), 0644); err != nil {
+				return fmt.Errorf("fail to rewrite %s: %s", backPointer, err)
+			}
+		}
+	}
+
+	v.Path = newPath
+	v.DotGit = newDotGit
+	return nil
+}
+
+// findWorktreeGitdirFile scans GitDir/worktrees/*/gitdir for the entry
+// whose back-pointer resolves to oldDotGit, rather than guessing the
+// worktree's admin-dir name from the project path (which git may have
+// de-duplicated, e.g. "foo1" when two projects share a basename). It
+// returns "" with no error when GitDir isn't a linked-worktree admin dir
+// at all (no worktrees/ subdirectory), and an error when worktrees/ exists
+// but no entry matches oldDotGit.
+func findWorktreeGitdirFile(gitDir, oldDotGit string) (string, error) {
+	absOldDotGit, err := filepath.Abs(oldDotGit)
+	if err != nil {
+		return "", err
+	}
+
+	worktreesDir := filepath.Join(gitDir, "worktrees")
+	entries, err := os.ReadDir(worktreesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("fail to list %s: %s", worktreesDir, err)
+	}
+
+	for _, entry := range entries {
+		gitdirFile := filepath.Join(worktreesDir, entry.Name(), "gitdir")
+		content, err := os.ReadFile(gitdirFile)
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(content)) == absOldDotGit {
+			return gitdirFile, nil
+		}
+	}
+	return "", fmt.Errorf("fail to find a worktree registration for %s under %s", oldDotGit, worktreesDir)
+}
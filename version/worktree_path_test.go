@@ -0,0 +1,112 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alibaba/git-repo-go/manifest"
+)
+
+func TestSetWorktreePathMovesRegistration(t *testing.T) {
+	base := t.TempDir()
+
+	mainDir := filepath.Join(base, "main")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, mainDir, "init")
+	runGit(t, mainDir, "config", "user.email", "test@example.com")
+	runGit(t, mainDir, "config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(mainDir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, mainDir, "add", "a.txt")
+	runGit(t, mainDir, "commit", "-m", "initial")
+
+	oldWorktree := filepath.Join(base, "old")
+	runGit(t, mainDir, "worktree", "add", oldWorktree, "-b", "topic")
+
+	gitDir := filepath.Join(mainDir, ".git")
+	repo := &Repository{GitDir: gitDir, Project: manifest.Project{Path: oldWorktree}, DotGit: filepath.Join(oldWorktree, ".git")}
+
+	newWorktree := filepath.Join(base, "new")
+	if err := os.Rename(oldWorktree, newWorktree); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.SetWorktreePath(newWorktree); err != nil {
+		t.Fatalf("SetWorktreePath: %s", err)
+	}
+
+	backPointer := filepath.Join(gitDir, "worktrees", "old", "gitdir")
+	got, err := os.ReadFile(backPointer)
+	if err != nil {
+		t.Fatalf("read back-pointer: %s", err)
+	}
+	wantDotGit, err := filepath.Abs(filepath.Join(newWorktree, ".git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(got)) != wantDotGit {
+		t.Errorf("back-pointer = %q, want %q", strings.TrimSpace(string(got)), wantDotGit)
+	}
+
+	out, err := exec.Command(GIT, "-C", mainDir, "worktree", "list", "--porcelain").CombinedOutput()
+	if err != nil {
+		t.Fatalf("worktree list: %s: %s", err, out)
+	}
+	if !strings.Contains(string(out), newWorktree) {
+		t.Errorf("worktree list does not mention relocated path %s:\n%s", newWorktree, out)
+	}
+
+	if out, err := exec.Command(GIT, "-C", newWorktree, "status", "--porcelain").CombinedOutput(); err != nil {
+		t.Errorf("git status in relocated worktree: %s: %s", err, out)
+	}
+}
+
+// TestSetWorktreePathFindsDeduplicatedAdminDir covers the case where git
+// gave the worktree an admin-dir name that doesn't match the project's
+// basename, e.g. because another project already claimed it and git
+// appended a suffix ("foo1" instead of "foo"). SetWorktreePath must find
+// the registration by matching gitdir content, not by guessing the name.
+func TestSetWorktreePathFindsDeduplicatedAdminDir(t *testing.T) {
+	base := t.TempDir()
+
+	gitDir := filepath.Join(base, "main", ".git")
+	oldWorktree := filepath.Join(base, "foo")
+	oldDotGit := filepath.Join(oldWorktree, ".git")
+
+	adminDir := filepath.Join(gitDir, "worktrees", "foo1")
+	if err := os.MkdirAll(adminDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	absOldDotGit, err := filepath.Abs(oldDotGit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(adminDir, "gitdir"), []byte(absOldDotGit+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &Repository{GitDir: gitDir, Project: manifest.Project{Path: oldWorktree}, DotGit: oldDotGit}
+
+	newWorktree := filepath.Join(base, "new")
+	if err := repo.SetWorktreePath(newWorktree); err != nil {
+		t.Fatalf("SetWorktreePath: %s", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	if err != nil {
+		t.Fatalf("read back-pointer: %s", err)
+	}
+	wantDotGit, err := filepath.Abs(filepath.Join(newWorktree, ".git"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(got)) != wantDotGit {
+		t.Errorf("back-pointer = %q, want %q", strings.TrimSpace(string(got)), wantDotGit)
+	}
+}
@@ -0,0 +1,21 @@
+package version
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ResolveWorktreeRealPath follows symlinks on the project's worktree path
+// (WorktreePath, not RepoDir - the latter points at the shared/bare
+// repository, not the checkout) and returns the canonical filesystem path.
+// Two manifest entries whose worktree paths differ only by a symlink
+// resolve to the same real path here, letting callers detect and skip the
+// duplicate.
+func (v Repository) ResolveWorktreeRealPath() (string, error) {
+	worktree := v.WorktreePath()
+	real, err := filepath.EvalSymlinks(worktree)
+	if err != nil {
+		return "", fmt.Errorf("fail to resolve real path of %s: %s", worktree, err)
+	}
+	return real, nil
+}
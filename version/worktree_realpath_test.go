@@ -0,0 +1,39 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alibaba/git-repo-go/manifest"
+)
+
+func TestResolveWorktreeRealPathDedupesSymlinkedProjects(t *testing.T) {
+	base := t.TempDir()
+
+	real := filepath.Join(base, "real")
+	if err := os.MkdirAll(real, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(real, link); err != nil {
+		t.Fatal(err)
+	}
+
+	direct := &Repository{Project: manifest.Project{Path: real}}
+	viaLink := &Repository{Project: manifest.Project{Path: link}}
+
+	directReal, err := direct.ResolveWorktreeRealPath()
+	if err != nil {
+		t.Fatalf("ResolveWorktreeRealPath (direct): %s", err)
+	}
+	linkReal, err := viaLink.ResolveWorktreeRealPath()
+	if err != nil {
+		t.Fatalf("ResolveWorktreeRealPath (via symlink): %s", err)
+	}
+
+	if directReal != linkReal {
+		t.Errorf("expected both projects to resolve to the same real path, got %q and %q", directReal, linkReal)
+	}
+}